@@ -34,8 +34,11 @@ func runServer(cmd *cobra.Command, args []string) {
 	// 加载配置
 	cfg := InitConfig(cmd, args)
 
-	// 设置日志
+	// 设置日志，退出前刷新并关闭日志文件
 	InitLogger(cfg)
+	defer func() {
+		_ = logger.Close()
+	}()
 
 	// 显示版本信息
 	vInfo := version.GetVersion()
@@ -41,26 +41,30 @@ type App struct {
 	WriteTimeout   time.Duration // 写入超时
 	IdleTimeout    time.Duration // 空闲超时
 	MaxHeaderBytes int           // 最大请求头大小
+	DebugResponse  bool          // 错误响应中返回错误链和堆栈，仅用于开发环境
 }
 
 // Config MySQL等数据库配置
 type Database struct {
-	Enabled         bool // 是否启用SQL数据库
-	UserName        string
-	Password        string
-	DBName          string
-	Host            string
-	Port            int
-	TablePrefix     string
-	Charset         string
-	ParseTime       bool
-	Loc             string
-	ShowLog         bool
-	MaxIdleConn     int
-	MaxOpenConn     int
-	ConnMaxLifeTime time.Duration
-	SlowThreshold   time.Duration // 慢查询时长，默认500ms
-	SSLMode         string        // SSL模式，默认disable，可选值：disable, require, verify-ca, verify-full
+	Enabled          bool // 是否启用SQL数据库
+	UserName         string
+	Password         string
+	DBName           string
+	Host             string
+	Port             int
+	TablePrefix      string
+	Charset          string
+	ParseTime        bool
+	Loc              string
+	ShowLog          bool
+	MaxIdleConn      int
+	MaxOpenConn      int
+	ConnMaxLifeTime  time.Duration
+	SlowThreshold    time.Duration            // 慢查询时长，默认500ms
+	SSLMode          string                   // SSL模式，默认disable，可选值：disable, require, verify-ca, verify-full
+	ExplainSlowQuery bool                     // 是否对慢查询执行EXPLAIN并记录执行计划，仅建议在非生产环境开启
+	SlowThresholds   map[string]time.Duration // 按表名覆盖慢查询时长，小于等于0表示不记录该表的慢查询
+	Replicas         []string                 // 只读副本DSN列表，配置后启用读写分离，读操作默认走副本
 }
 
 // Config jwt config
@@ -0,0 +1,113 @@
+package response
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/errspec"
+)
+
+// BatchItem 批量操作中单项的处理结果
+type BatchItem[T any] struct {
+	ID      any    `json:"id,omitempty"`   // 条目标识
+	Code    int    `json:"code"`           // 错误码，0表示成功
+	Message string `json:"message"`        // 提示信息
+	Data    T      `json:"data,omitempty"` // 数据
+}
+
+// BatchResult 批量操作结果
+type BatchResult[T any] struct {
+	Total     int            `json:"total"`     // 总条数
+	Succeeded int            `json:"succeeded"` // 成功条数
+	Failed    int            `json:"failed"`    // 失败条数
+	Items     []BatchItem[T] `json:"items"`     // 每项的处理结果
+}
+
+// BatchCodes 批量响应顶层使用的错误码
+type BatchCodes struct {
+	Success int // 全部成功
+	Partial int // 部分成功
+	Failed  int // 全部失败
+}
+
+var (
+	batchCodes = BatchCodes{
+		Success: errspec.Success.Code(),
+		Partial: errspec.ErrBatchPartialSuccess.Code(),
+		Failed:  errspec.ErrBatchFailed.Code(),
+	}
+	batchCodesMu sync.RWMutex
+)
+
+// RegisterBatchCodes 注册批量响应顶层使用的错误码，覆盖默认值
+func RegisterBatchCodes(codes BatchCodes) {
+	batchCodesMu.Lock()
+	defer batchCodesMu.Unlock()
+
+	batchCodes = codes
+}
+
+// BatchSuccess 创建成功的批量条目
+func BatchSuccess[T any](id any, data T) BatchItem[T] {
+	return BatchItem[T]{
+		ID:      id,
+		Code:    errspec.Success.Code(),
+		Message: "success",
+		Data:    data,
+	}
+}
+
+// BatchFailure 创建失败的批量条目，错误码从错误中获取
+func BatchFailure[T any](id any, err error) BatchItem[T] {
+	code := errspec.ErrUnknown.Code()
+	if e, ok := err.(interface{ Code() int }); ok {
+		code = e.Code()
+	}
+
+	return BatchItem[T]{
+		ID:      id,
+		Code:    code,
+		Message: err.Error(),
+	}
+}
+
+// MultiStatus 返回批量操作响应
+// 顶层错误码区分全部成功、部分成功和全部失败，客户端可直接据此分支处理
+func MultiStatus[T any](c *gin.Context, items []BatchItem[T]) {
+	result := BatchResult[T]{
+		Total: len(items),
+		Items: items,
+	}
+	for _, item := range items {
+		if item.Code == errspec.Success.Code() {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+
+	batchCodesMu.RLock()
+	codes := batchCodes
+	batchCodesMu.RUnlock()
+
+	ctx := c.Request.Context()
+	code, status, message := codes.Success, http.StatusOK, "success"
+	switch {
+	case result.Failed > 0 && result.Succeeded > 0:
+		code = codes.Partial
+		status = http.StatusMultiStatus
+		message = errspec.ErrBatchPartialSuccess.New(ctx).Error()
+	case result.Failed > 0:
+		code = codes.Failed
+		status = http.StatusMultiStatus
+		message = errspec.ErrBatchFailed.New(ctx).Error()
+	}
+
+	writeJSON(c, status, timestamped(Result[BatchResult[T]]{
+		Code:      code,
+		Message:   localizeMessage(c, code, message),
+		Data:      result,
+		RequestID: getRequestID(c),
+	}))
+}
@@ -0,0 +1,146 @@
+package response
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCompressMinSize 默认的最小压缩字节数，小于该大小的响应不压缩
+const DefaultCompressMinSize = 1024
+
+// WithCompression 设置是否根据 Accept-Encoding 压缩响应，支持 gzip 和 deflate，默认关闭
+func WithCompression(enabled bool) Option {
+	return func(c *config) {
+		c.compress = enabled
+	}
+}
+
+// WithCompressionMinSize 设置最小压缩字节数，默认为 DefaultCompressMinSize
+// 压缩小响应得不偿失，反而会增加 CPU 开销和响应大小
+func WithCompressionMinSize(size int) Option {
+	return func(c *config) {
+		c.compressMinSize = size
+	}
+}
+
+// writeData 写入响应体，开启压缩且客户端支持时压缩后写入
+func writeData(c *gin.Context, status int, contentType string, data []byte) {
+	if negotiable(c, contentType, len(data)) {
+		// 是否压缩取决于 Accept-Encoding，未压缩的响应同样需要 Vary，
+		// 避免缓存把未压缩的副本返回给支持 gzip 的客户端，或者相反
+		addVary(c, "Accept-Encoding")
+		if encoding := negotiateEncoding(c.GetHeader("Accept-Encoding")); encoding != "" {
+			if compressed, err := compress(encoding, data); err == nil {
+				c.Header("Content-Encoding", encoding)
+				data = compressed
+			}
+		}
+	}
+
+	c.Header("Content-Length", strconv.Itoa(len(data)))
+	c.Data(status, contentType, data)
+}
+
+// negotiable 判断响应是否按 Accept-Encoding 协商压缩
+// 未开启压缩、已设置编码、响应过小或内容本身已压缩时不参与协商
+func negotiable(c *gin.Context, contentType string, size int) bool {
+	cfg := getConfig()
+	if !cfg.compress || c.Writer.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+
+	minSize := cfg.compressMinSize
+	if minSize <= 0 {
+		minSize = DefaultCompressMinSize
+	}
+	return size >= minSize && !isCompressedType(contentType)
+}
+
+// addVary 在 Vary 响应头中追加字段，已存在时不重复添加
+func addVary(c *gin.Context, field string) {
+	header := c.Writer.Header()
+	for _, value := range header.Values("Vary") {
+		for _, existing := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(existing), field) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", field)
+}
+
+// negotiateEncoding 根据 Accept-Encoding 选择编码，优先使用 gzip，忽略 q=0 的编码
+func negotiateEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		accepted[name] = true
+	}
+
+	switch {
+	case accepted["gzip"] || accepted["*"]:
+		return "gzip"
+	case accepted["deflate"]:
+		return "deflate"
+	default:
+		return ""
+	}
+}
+
+// isCompressedType 判断内容类型是否已经是压缩格式，如图片、视频和压缩包
+func isCompressedType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(contentType)
+	}
+
+	switch {
+	case mediaType == "image/svg+xml":
+		return false
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"):
+		return true
+	}
+
+	switch mediaType {
+	case "application/zip", "application/gzip", "application/x-gzip",
+		"application/x-7z-compressed", "application/x-rar-compressed",
+		"application/x-bzip2", "application/x-xz", "application/zstd", "application/pdf":
+		return true
+	}
+	return false
+}
+
+// compress 使用指定编码压缩数据
+func compress(encoding string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var w io.WriteCloser
+	if encoding == "gzip" {
+		w = gzip.NewWriter(&buf)
+	} else {
+		w = zlib.NewWriter(&buf)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
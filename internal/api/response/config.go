@@ -0,0 +1,108 @@
+package response
+
+import "sync"
+
+// config 响应的全局配置
+type config struct {
+	successCode    int         // 成功码
+	successMessage string      // 成功提示信息
+	debug          bool        // 错误响应中是否返回调试信息
+	sanitizeErrors bool        // 是否隐藏没有错误码的错误的原始消息
+	fieldNaming    FieldNaming // JSON 字段命名风格
+	timeFormat     TimeFormat  // 时间戳格式
+
+	compress        bool // 是否压缩响应
+	compressMinSize int  // 最小压缩字节数
+
+	chainMinStatus int              // 记录错误链的最低HTTP状态码
+	chainSkipCodes map[int]struct{} // 不记录错误链的错误码
+}
+
+// Option 响应配置选项
+type Option func(*config)
+
+var (
+	defaultConfig = config{
+		successCode:    0,
+		successMessage: "success",
+	}
+	configMu sync.RWMutex
+)
+
+// WithSuccessCode 设置成功响应的业务码，默认为0
+func WithSuccessCode(code int) Option {
+	return func(c *config) {
+		c.successCode = code
+	}
+}
+
+// WithSuccessMessage 设置成功响应的默认提示信息，默认为 success
+func WithSuccessMessage(msg string) Option {
+	return func(c *config) {
+		c.successMessage = msg
+	}
+}
+
+// WithDebug 设置是否在错误响应的 debug 字段中返回错误链和堆栈
+// 会暴露内部实现细节，只应在开发环境开启
+func WithDebug(debug bool) Option {
+	return func(c *config) {
+		c.debug = debug
+	}
+}
+
+// WithSanitizeErrors 设置是否隐藏没有错误码的错误的原始消息，应在生产环境开启
+// 开启后数据库驱动等返回的未定义错误码的错误可能包含表名、SQL 片段等内部细节，
+// 响应中统一使用 ErrInternal 的消息，原始消息只记录到日志；errspec 定义的错误不受影响。
+func WithSanitizeErrors(enabled bool) Option {
+	return func(c *config) {
+		c.sanitizeErrors = enabled
+	}
+}
+
+// WithErrorChainMinStatus 设置错误日志中记录 error_chain 的最低HTTP状态码
+// 如设为 500 时，参数错误、资源不存在等 4xx 错误只记录错误码和提示信息。默认所有错误都记录
+func WithErrorChainMinStatus(status int) Option {
+	return func(c *config) {
+		c.chainMinStatus = status
+	}
+}
+
+// WithoutErrorChainCodes 设置错误日志中不记录 error_chain 的错误码
+// 重复调用时覆盖之前的设置，不传参数时清空
+func WithoutErrorChainCodes(codes ...int) Option {
+	return func(c *config) {
+		skip := make(map[int]struct{}, len(codes))
+		for _, code := range codes {
+			skip[code] = struct{}{}
+		}
+		c.chainSkipCodes = skip
+	}
+}
+
+// logErrorChain 判断错误日志中是否记录错误链
+func (c config) logErrorChain(httpStatus, code int) bool {
+	if httpStatus < c.chainMinStatus {
+		return false
+	}
+	_, skip := c.chainSkipCodes[code]
+	return !skip
+}
+
+// Configure 修改响应的全局配置，应在启动时调用
+func Configure(opts ...Option) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	for _, opt := range opts {
+		opt(&defaultConfig)
+	}
+}
+
+// getConfig 获取当前配置
+func getConfig() config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+
+	return defaultConfig
+}
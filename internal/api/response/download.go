@@ -0,0 +1,92 @@
+package response
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/pkg/logger"
+)
+
+// sniffLen 推断内容类型时读取的字节数
+const sniffLen = 512
+
+// Download 以附件形式返回文件内容，不使用JSON响应结构
+// reader 实现了 io.ReadSeeker 时（如 *os.File）支持 Range 请求，可以断点续传；
+// 否则以流的形式输出，不支持 Range。contentType 为空时按文件名或内容推断。
+// 写入响应前读取失败时通过 Error 返回标准的错误响应，reader 为 nil 时返回 ErrNotFound；
+// 已开始写入后出错只记录日志。reader 由调用方负责关闭。
+func Download(c *gin.Context, reader io.Reader, filename, contentType string) {
+	if reader == nil {
+		Error(c, errspec.ErrNotFound.New(c.Request.Context()))
+		return
+	}
+
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		serveSeeker(c, seeker, filename, contentType)
+		return
+	}
+
+	// 先读取开头的数据，读取失败时还可以返回错误响应
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(reader, head)
+	head = head[:n]
+	eof := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !eof {
+		Error(c, err)
+		return
+	}
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(filename))
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(head)
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Disposition", attachmentDisposition(filename))
+	header.Set("Accept-Ranges", "none")
+	if eof {
+		header.Set("Content-Length", strconv.Itoa(len(head)))
+	}
+	c.Status(http.StatusOK)
+
+	if _, err := c.Writer.Write(head); err != nil || eof {
+		return
+	}
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		logger.WarnContext(c.Request.Context(), "Download interrupted", "filename", filename, "error", err)
+	}
+}
+
+// serveSeeker 输出可定位的内容，支持 Range 和条件请求
+func serveSeeker(c *gin.Context, seeker io.ReadSeeker, filename, contentType string) {
+	// 提前检查能否定位，http.ServeContent 出错时只会返回纯文本错误
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		Error(c, err)
+		return
+	}
+
+	header := c.Writer.Header()
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	header.Set("Content-Disposition", attachmentDisposition(filename))
+	http.ServeContent(c.Writer, c.Request, filename, time.Time{}, seeker)
+}
+
+// attachmentDisposition 生成附件形式的 Content-Disposition，非 ASCII 文件名按 RFC 2231 编码
+func attachmentDisposition(filename string) string {
+	if filename == "" {
+		return "attachment"
+	}
+	return mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+}
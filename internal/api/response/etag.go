@@ -0,0 +1,71 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuccessWithETag 返回带 ETag 的成功响应
+// 请求的 If-None-Match 与 etag 匹配时返回 304 且不带响应体，否则设置 ETag 响应头并返回标准的成功响应。
+// etag 为空时按 data 的 JSON 内容计算强 ETag；未加引号的 etag 会自动加上引号，W/ 开头的视为弱 ETag。
+// 只有 GET 和 HEAD 请求会返回 304。
+func SuccessWithETag[T any](c *gin.Context, data T, etag string, msg ...string) {
+	if etag == "" {
+		etag = ComputeETag(data)
+	} else {
+		etag = quoteETag(etag)
+	}
+
+	if etag != "" {
+		c.Header("ETag", etag)
+		method := c.Request.Method
+		if (method == http.MethodGet || method == http.MethodHead) && etagMatch(c.GetHeader("If-None-Match"), etag) {
+			c.Header("X-Request-ID", getRequestID(c))
+			c.Status(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+	}
+
+	success(c, http.StatusOK, data, msg...)
+}
+
+// ComputeETag 根据数据的 JSON 内容计算强 ETag，序列化失败时返回空字符串
+// 只对 data 计算，不包含响应结构中的时间、请求ID等每次都会变化的字段。
+func ComputeETag(data any) string {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// quoteETag 为未加引号的 ETag 加上引号
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// etagMatch 判断 If-None-Match 是否与 ETag 匹配
+// If-None-Match 使用弱比较，忽略 W/ 前缀，* 匹配任意 ETag
+func etagMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package response
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/model"
+)
+
+// filterOperators 查询参数值中支持的运算符前缀，如 age=gte:18
+var filterOperators = map[string]string{
+	"eq":   "= ?",
+	"ne":   "<> ?",
+	"gt":   "> ?",
+	"gte":  ">= ?",
+	"lt":   "< ?",
+	"lte":  "<= ?",
+	"like": "LIKE ? ESCAPE ?",
+	"in":   "IN ?",
+}
+
+// likeEscaper 转义 LIKE 模式中的通配符，使客户端传入的 % 和 _ 按字面匹配
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// QueryOptionsFromRequest 根据查询参数构造过滤条件
+// allowedFields 为允许过滤的参数名到数据库列名的映射，不在映射中的参数被忽略，
+// 客户端只能使用映射中的参数名，无法直接指定列名，也无法得知实际的列名。
+// 参数值可以带运算符前缀：eq（默认）、ne、gt、gte、lt、lte、like（包含，% 和 _ 按字面匹配）、in（逗号分隔），
+// 如 ?age=gte:18&age=lte:60&status=in:1,2&name=like:tom。前缀不是已知运算符时整个值按等值比较，
+// 值为空的参数被忽略。条件之间为 AND 关系，值均以参数绑定，不会拼接到 SQL 中。
+func QueryOptionsFromRequest(c *gin.Context, allowedFields map[string]string) *model.QueryOptions {
+	opts := &model.QueryOptions{}
+
+	// 按参数名排序，保证生成的条件顺序稳定
+	names := make([]string, 0, len(allowedFields))
+	for name := range allowedFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var conditions []string
+	for _, name := range names {
+		column := allowedFields[name]
+		for _, raw := range c.QueryArray(name) {
+			op, value := parseFilterValue(raw)
+			if value == "" {
+				continue
+			}
+
+			args := []any{value}
+			switch op {
+			case "like":
+				// 转义字符以参数绑定，MySQL 中 '\' 字面量会转义结尾的引号
+				args = []any{"%" + likeEscaper.Replace(value) + "%", `\`}
+			case "in":
+				args = []any{strings.Split(value, ",")}
+			}
+			conditions = append(conditions, column+" "+filterOperators[op])
+			opts.Args = append(opts.Args, args...)
+		}
+	}
+	opts.Condition = strings.Join(conditions, " AND ")
+
+	return opts
+}
+
+// parseFilterValue 解析参数值中的运算符前缀，没有已知前缀时为等值比较
+func parseFilterValue(raw string) (op, value string) {
+	if prefix, rest, found := strings.Cut(raw, ":"); found {
+		if _, ok := filterOperators[prefix]; ok {
+			return prefix, rest
+		}
+	}
+	return "eq", raw
+}
@@ -0,0 +1,81 @@
+package response
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/epkgs/i18n"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+var (
+	// messages 按语言和错误码组织的错误消息
+	messages   = map[string]map[int]string{}
+	messagesMu sync.RWMutex
+)
+
+// RegisterMessages 注册指定语言的错误消息，key 为错误码
+// 同一语言多次注册时合并，后注册的消息覆盖先注册的
+func RegisterMessages(lang string, msgs map[int]string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+
+	lang = normalizeLang(lang)
+	if messages[lang] == nil {
+		messages[lang] = make(map[int]string, len(msgs))
+	}
+	for code, msg := range msgs {
+		messages[lang][code] = msg
+	}
+}
+
+// localizeMessage 根据请求语言解析错误码对应的消息，找不到时返回 fallback
+func localizeMessage(c *gin.Context, code int, fallback string) string {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+
+	if len(messages) == 0 {
+		return fallback
+	}
+
+	for _, lang := range requestLanguages(c) {
+		lang = normalizeLang(lang)
+
+		// 先精确匹配，再匹配主语言，如 zh-cn 回退到 zh
+		if msg, ok := messages[lang][code]; ok {
+			return msg
+		}
+		if base, _, found := strings.Cut(lang, "-"); found {
+			if msg, ok := messages[base][code]; ok {
+				return msg
+			}
+		}
+	}
+
+	return fallback
+}
+
+// requestLanguages 获取请求接受的语言列表
+// 优先使用 i18n 中间件解析的结果，其次解析 Accept-Language 请求头
+func requestLanguages(c *gin.Context) []string {
+	if langs := i18n.GetAcceptLanguages(c.Request.Context()); len(langs) > 0 {
+		return langs
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	if err != nil {
+		return nil
+	}
+
+	langs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		langs = append(langs, tag.String())
+	}
+	return langs
+}
+
+// normalizeLang 统一语言标识的写法，如 zh_CN 转为 zh-cn
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
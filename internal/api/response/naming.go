@@ -0,0 +1,115 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldNaming JSON 字段命名风格
+type FieldNaming int
+
+const (
+	SnakeCase FieldNaming = iota // 下划线命名，如 request_id，默认值
+	CamelCase                    // 小驼峰命名，如 requestId
+)
+
+// WithFieldNaming 设置响应 JSON 的字段命名风格，默认为 SnakeCase
+// 使用 CamelCase 时，响应结构和 data 中的字段名都会从下划线命名转换为小驼峰命名，
+// data 中 map 的键同样会被转换。
+func WithFieldNaming(naming FieldNaming) Option {
+	return func(c *config) {
+		c.fieldNaming = naming
+	}
+}
+
+// writeJSON 按配置的字段命名风格写入 JSON 响应，开启压缩时压缩响应体
+func writeJSON(c *gin.Context, status int, obj any) {
+	cfg := getConfig()
+	if cfg.fieldNaming != CamelCase && !cfg.compress {
+		c.JSON(status, obj)
+		return
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if cfg.fieldNaming == CamelCase {
+		data, err = marshalCamelCase(obj)
+	} else {
+		data, err = json.Marshal(obj)
+	}
+	if err != nil {
+		// 转换失败时退回默认的序列化方式，保证响应仍然可用
+		c.JSON(status, obj)
+		return
+	}
+
+	writeData(c, status, "application/json; charset=utf-8", data)
+}
+
+// marshalCamelCase 序列化对象并将所有字段名转换为小驼峰命名
+func marshalCamelCase(obj any) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用 json.Number 保留数字的原始精度
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(camelCaseKeys(value))
+}
+
+// camelCaseKeys 递归转换对象中的字段名
+func camelCaseKeys(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			converted[toCamelCase(key)] = camelCaseKeys(item)
+		}
+		return converted
+	case []any:
+		for i, item := range v {
+			v[i] = camelCaseKeys(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// toCamelCase 将下划线命名转换为小驼峰命名，如 page_size 转为 pageSize
+// 首尾的下划线保留原样
+func toCamelCase(key string) string {
+	core := strings.Trim(key, "_")
+	if !strings.Contains(core, "_") {
+		return key
+	}
+
+	start := strings.Index(key, core)
+	parts := strings.Split(core, "_")
+
+	var b strings.Builder
+	b.WriteString(key[:start])
+	b.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	b.WriteString(key[start+len(core):])
+	return b.String()
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/model"
+)
+
+const (
+	// QueryPage 页码查询参数名
+	QueryPage = "page"
+	// QueryPageSize 每页条数查询参数名
+	QueryPageSize = "page_size"
+)
+
+// DefaultPageSize 未指定每页条数时的默认值
+var DefaultPageSize = 20
+
+// ParsePagination 从查询参数中解析分页参数
+// 缺失或无效的页码视为 1，缺失或无效的每页条数使用 DefaultPageSize，
+// 超出 model.DefaultMaxPageSize 时取该值，与仓库的分页上限保持一致。
+// 不会返回错误，保证所有列表接口的分页行为一致。
+func ParsePagination(c *gin.Context) (page, pageSize int) {
+	page = parsePositiveInt(c.Query(QueryPage), 1)
+	pageSize = parsePositiveInt(c.Query(QueryPageSize), DefaultPageSize)
+	if maxPageSize := model.DefaultMaxPageSize; maxPageSize > 0 && pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+// parsePositiveInt 解析正整数，解析失败或不大于 0 时返回默认值
+func parsePositiveInt(s string, def int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,45 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/pkg/errorx"
+	"github.com/limitcool/starter/internal/pkg/logger"
+)
+
+// Raw 直接以 JSON 返回 body，不使用 Result 响应结构
+// 用于必须遵循第三方协议的接口，如 Stripe、GitHub 的 Webhook 回调要求特定的响应格式。
+// body 按原样序列化，不做字段命名转换；响应压缩和 X-Request-ID 响应头与标准响应一致。
+// 状态码不小于 400 时与 Error 一样记录错误日志，处理函数通过 c.Error(err) 附加的错误会记录其错误链。
+// 自己的 API 应使用 Success、Error 等标准响应方法。
+func Raw(c *gin.Context, status int, body any) {
+	ctx := c.Request.Context()
+	requestID := getRequestID(c)
+
+	if status >= http.StatusBadRequest {
+		keyvals := []any{
+			"status", status,
+			"trace_id", getTraceIDFromContext(c),
+			"request_id", requestID,
+			"path", c.Request.URL.Path,
+			"method", c.Request.Method,
+			"client_ip", c.ClientIP(),
+		}
+		if last := c.Errors.Last(); last != nil {
+			keyvals = append(keyvals, "error_chain", errorx.FormatErrorChain(last.Err))
+		}
+		logger.ErrorContext(ctx, "API error occurred", keyvals...)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to encode raw response", "error", err, "request_id", requestID)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Header("X-Request-ID", requestID)
+	writeData(c, status, "application/json; charset=utf-8", data)
+}
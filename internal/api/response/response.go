@@ -2,7 +2,11 @@
 package response
 
 import (
+	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,58 +14,171 @@ import (
 	"github.com/limitcool/starter/internal/errspec"
 	"github.com/limitcool/starter/internal/pkg/errorx"
 	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Result API标准响应结构
 type Result[T any] struct {
-	Code      int    `json:"code"`                 // 错误码
-	Message   string `json:"message"`              // 提示信息
-	Data      T      `json:"data"`                 // 数据
-	RequestID string `json:"request_id,omitempty"` // 请求ID
-	Time      int64  `json:"timestamp,omitempty"`  // 时间戳
-	TraceID   string `json:"trace_id,omitempty"`   // 链路追踪ID
+	Code      int        `json:"code"`                 // 错误码
+	Message   string     `json:"message"`              // 提示信息
+	Data      T          `json:"data"`                 // 数据
+	RequestID string     `json:"request_id,omitempty"` // 请求ID
+	Time      int64      `json:"timestamp,omitempty"`  // Unix 秒级时间戳，输出格式由 WithTimeFormat 决定
+	TraceID   string     `json:"trace_id,omitempty"`   // 链路追踪ID
+	Details   any        `json:"details,omitempty"`    // 错误详情，如参数校验失败的字段
+	Debug     *DebugInfo `json:"debug,omitempty"`      // 调试信息，仅在开启调试模式时返回
+
+	at time.Time // 创建响应时的精确时间，用于毫秒和 RFC3339 格式
+}
+
+// DebugInfo 错误的调试信息
+type DebugInfo struct {
+	ErrorChain string `json:"error_chain"`     // 完整的错误链
+	Stack      string `json:"stack,omitempty"` // 错误创建时的堆栈
 }
 
 // PageResult 分页结果
 type PageResult[T any] struct {
-	Total    int64 `json:"total"`     // 总记录数
-	Page     int   `json:"page"`      // 当前页码
-	PageSize int   `json:"page_size"` // 每页大小
-	List     T     `json:"list"`      // 数据列表
+	Total      int64 `json:"total"`       // 总记录数
+	Page       int   `json:"page"`        // 当前页码
+	PageSize   int   `json:"page_size"`   // 每页大小
+	TotalPages int   `json:"total_pages"` // 总页数
+	HasNext    bool  `json:"has_next"`    // 是否有下一页
+	HasPrev    bool  `json:"has_prev"`    // 是否有上一页
+	List       T     `json:"list"`        // 数据列表
 }
 
 // NewPageResult 创建分页结果
 func NewPageResult[T any](list T, total int64, page, pageSize int) *PageResult[T] {
+	// 每页大小无效时无法计算总页数，视为0页
+	totalPages := 0
+	if pageSize > 0 && total > 0 {
+		totalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+
 	return &PageResult[T]{
-		Total:    total,
-		Page:     page,
-		PageSize: pageSize,
-		List:     list,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1 && totalPages > 0,
+		List:       list,
+	}
+}
+
+// CursorPageResult 游标分页结果，用于无限滚动等不需要总数的列表
+type CursorPageResult[T any] struct {
+	List       T      `json:"list"`        // 数据列表
+	NextCursor string `json:"next_cursor"` // 下一页的游标，没有更多数据时为空
+	HasMore    bool   `json:"has_more"`    // 是否还有下一页
+}
+
+// NewCursorPageResult 创建游标分页结果，nextCursor 不为空时表示还有下一页
+func NewCursorPageResult[T any](list T, nextCursor string) *CursorPageResult[T] {
+	return &CursorPageResult[T]{
+		List:       list,
+		NextCursor: nextCursor,
+		HasMore:    nextCursor != "",
+	}
+}
+
+// MapPage 转换分页结果的元素类型，保留分页信息
+func MapPage[T, U any](p *PageResult[[]T], fn func(T) U) *PageResult[[]U] {
+	if p == nil {
+		return nil
+	}
+
+	return NewPageResult(MapSlice(p.List, fn), p.Total, p.Page, p.PageSize)
+}
+
+// MapSlice 转换切片的元素类型，如将实体列表转换为 DTO 列表
+// list 为 nil 时返回空切片，保证序列化为 [] 而不是 null
+func MapSlice[T, U any](list []T, fn func(T) U) []U {
+	result := make([]U, 0, len(list))
+	for _, item := range list {
+		result = append(result, fn(item))
 	}
+	return result
 }
 
 // Success 返回成功响应
 func Success[T any](c *gin.Context, data T, msg ...string) {
-	message := "success"
-	if len(msg) > 0 {
-		message = msg[0]
+	success(c, http.StatusOK, data, msg...)
+}
+
+// Page 返回分页成功响应
+func Page[T any](c *gin.Context, list T, total int64, page, pageSize int, msg ...string) {
+	success(c, http.StatusOK, NewPageResult(list, total, page, pageSize), msg...)
+}
+
+// CursorPage 返回游标分页成功响应
+func CursorPage[T any](c *gin.Context, list T, nextCursor string, msg ...string) {
+	success(c, http.StatusOK, NewCursorPageResult(list, nextCursor), msg...)
+}
+
+// Created 返回创建成功响应，HTTP状态码为201
+func Created[T any](c *gin.Context, data T, msg ...string) {
+	success(c, http.StatusCreated, data, msg...)
+}
+
+// NoContent 返回无内容响应，HTTP状态码为204
+// 204 响应不能携带响应体，请求ID通过 X-Request-ID 响应头返回
+func NoContent(c *gin.Context) {
+	c.Header("X-Request-ID", getRequestID(c))
+	c.Status(http.StatusNoContent)
+	c.Writer.WriteHeaderNow()
+}
+
+// BinaryOption 二进制响应选项
+type BinaryOption func(*binaryOptions)
+
+// binaryOptions 二进制响应配置
+type binaryOptions struct {
+	disposition string // inline 或 attachment
+	filename    string // 文件名
+}
+
+// WithAttachment 以附件形式下载
+func WithAttachment(filename string) BinaryOption {
+	return func(o *binaryOptions) {
+		o.disposition = "attachment"
+		o.filename = filename
 	}
+}
 
-	// 获取请求ID
-	requestID := getRequestID(c)
+// WithInline 在浏览器中直接展示
+func WithInline(filename string) BinaryOption {
+	return func(o *binaryOptions) {
+		o.disposition = "inline"
+		o.filename = filename
+	}
+}
 
-	c.JSON(http.StatusOK, Result[T]{
-		Code:      0, // 成功码为0
-		Message:   message,
-		Data:      data,
-		RequestID: requestID,
-		Time:      time.Now().Unix(),
-	})
+// Binary 返回二进制数据，不使用JSON响应结构
+func Binary(c *gin.Context, contentType string, data []byte, opts ...BinaryOption) {
+	var o binaryOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.disposition != "" {
+		params := map[string]string{}
+		if o.filename != "" {
+			params["filename"] = o.filename
+		}
+		c.Header("Content-Disposition", mime.FormatMediaType(o.disposition, params))
+	}
+
+	writeData(c, http.StatusOK, contentType, data)
 }
 
-// SuccessNoData 返回无数据的成功响应
-func SuccessNoData(c *gin.Context, msg ...string) {
-	message := "success"
+// success 使用指定的HTTP状态码返回成功响应
+func success[T any](c *gin.Context, status int, data T, msg ...string) {
+	cfg := getConfig()
+
+	message := cfg.successMessage
 	if len(msg) > 0 {
 		message = msg[0]
 	}
@@ -69,17 +186,42 @@ func SuccessNoData(c *gin.Context, msg ...string) {
 	// 获取请求ID
 	requestID := getRequestID(c)
 
-	c.JSON(http.StatusOK, Result[struct{}]{
-		Code:      0, // 成功码为0
+	writeJSON(c, status, timestamped(Result[T]{
+		Code:      cfg.successCode,
 		Message:   message,
-		Data:      struct{}{},
+		Data:      data,
 		RequestID: requestID,
-		Time:      time.Now().Unix(),
-	})
+	}))
+}
+
+// SuccessNoData 返回无数据的成功响应
+func SuccessNoData(c *gin.Context, msg ...string) {
+	success(c, http.StatusOK, struct{}{}, msg...)
 }
 
 // Error 返回错误响应
 func Error(c *gin.Context, err error) {
+	writeError(c, err, struct{}{})
+}
+
+// ErrorWithData 返回携带数据的错误响应，适用于部分失败的场景，如批量导入的逐行错误
+func ErrorWithData[T any](c *gin.Context, err error, data T) {
+	writeError(c, err, data)
+}
+
+// TooManyRequests 返回限流错误响应
+// 使用 ErrTooManyRequests 错误码和 429 状态码，retryAfter 大于 0 时设置 Retry-After 响应头，
+// 单位为秒并向上取整，如 1.5s 设置为 2。在中间件中使用时调用方需自行 c.Abort()
+func TooManyRequests(c *gin.Context, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		seconds := int64((retryAfter + time.Second - 1) / time.Second)
+		c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	writeError(c, errspec.ErrTooManyRequests.New(c.Request.Context()), struct{}{})
+}
+
+// writeError 记录错误日志并输出错误响应
+func writeError[T any](c *gin.Context, err error, data T) {
 
 	var (
 		errorCode  = errspec.ErrUnknown.Code()
@@ -90,12 +232,44 @@ func Error(c *gin.Context, err error) {
 	// 获取请求上下文
 	ctx := c.Request.Context()
 
-	if e, ok := err.(interface{ Code() int }); ok {
+	// 顶层错误没有错误码时，使用错误链中最近的业务错误的错误码和状态码，
+	// 避免包装后的 404 等错误被当作 500 返回，同时保留外层有意转换的错误码
+	coded := err
+	if _, ok := err.(interface{ Code() int }); !ok {
+		if nearest := errorx.NearestError(err); nearest != nil {
+			coded = nearest
+			message = nearest.Error()
+		}
+	}
+
+	hasCode := false
+	if e, ok := coded.(interface{ Code() int }); ok {
 		errorCode = e.Code()
+		hasCode = true
 	}
 
-	if e, ok := err.(interface{ HttpStatus() int }); ok {
+	// 优先使用注册的状态码映射，其次使用错误自身的状态码，
+	// 只有错误码的错误按错误码注册表中的定义确定状态码
+	status, mapped := mapStatus(err)
+	if mapped {
+		httpStatus = status
+	} else if e, ok := coded.(interface{ HttpStatus() int }); ok {
 		httpStatus = e.HttpStatus()
+	} else if spec, ok := errspec.Lookup(errorCode); ok && hasCode {
+		httpStatus = spec.HttpStatus
+	}
+
+	// 参数校验错误返回422，并给出每个字段的错误信息
+	var details any
+	if fields, ok := ValidationDetails(err); ok {
+		details = fields
+		if errorCode == errspec.ErrUnknown.Code() || errorCode == errspec.ErrInvalidParams.Code() {
+			errorCode = errspec.ErrInvalidParams.Code()
+			message = validationMessage(c, fields)
+		}
+		if !mapped {
+			httpStatus = http.StatusUnprocessableEntity
+		}
 	}
 
 	// 获取请求ID
@@ -104,8 +278,9 @@ func Error(c *gin.Context, err error) {
 	// 获取链路追踪ID
 	traceID := getTraceIDFromContext(c)
 
-	// 记录错误到日志
-	logger.ErrorContext(ctx, "API error occurred",
+	// 记录错误到日志，预期内的客户端错误可配置为不记录错误链
+	cfg := getConfig()
+	keyvals := []any{
 		"code", errorCode,
 		"message", message,
 		"trace_id", traceID,
@@ -113,18 +288,66 @@ func Error(c *gin.Context, err error) {
 		"path", c.Request.URL.Path,
 		"method", c.Request.Method,
 		"client_ip", c.ClientIP(),
-		"error_chain", errorx.FormatErrorChain(err),
-	)
+	}
+	// 使用了自定义消息时同时记录原始消息
+	customized := false
+	if e, ok := coded.(interface{ OriginalMessage() string }); ok && e.OriginalMessage() != message {
+		customized = true
+		keyvals = append(keyvals, "original_message", e.OriginalMessage())
+	}
+	if cfg.logErrorChain(httpStatus, errorCode) {
+		keyvals = append(keyvals, "error_chain", errorx.FormatErrorChain(err))
+	}
+	logger.ErrorContext(ctx, "API error occurred", keyvals...)
 
-	// 统一响应结构
-	c.JSON(httpStatus, Result[struct{}]{
+	// 调试模式下返回错误链和堆栈
+	var debugInfo *DebugInfo
+	if cfg.debug {
+		debugInfo = newDebugInfo(err)
+	}
+
+	// 统一响应结构，消息按请求语言解析，自定义消息原样返回；
+	// 开启消息隐藏时，没有错误码的错误只返回通用的错误码和消息，避免泄露内部细节
+	switch {
+	case cfg.sanitizeErrors && !hasCode && details == nil:
+		errorCode = errspec.ErrInternal.Code()
+		message = errspec.ErrInternal.New(ctx).Error()
+		message = localizeMessage(c, errorCode, message)
+	case !customized:
+		message = localizeMessage(c, errorCode, message)
+	}
+	writeJSON(c, httpStatus, timestamped(Result[T]{
 		Code:      errorCode,
 		Message:   message,
-		Data:      struct{}{},
+		Data:      data,
 		RequestID: requestID,
-		Time:      time.Now().Unix(),
 		TraceID:   traceID,
-	})
+		Details:   details,
+		Debug:     debugInfo,
+	}))
+}
+
+// newDebugInfo 生成错误的调试信息
+func newDebugInfo(err error) *DebugInfo {
+	// 逐层展开错误链，跳过已包含在上一层消息中的原因
+	chain := []string{errorx.FormatErrorChain(err)}
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		msg := cause.Error()
+		if !strings.HasSuffix(chain[len(chain)-1], msg) {
+			chain = append(chain, msg)
+		}
+	}
+
+	info := &DebugInfo{
+		ErrorChain: strings.Join(chain, "\n"),
+	}
+
+	var tracer interface{ StackTrace() errors.StackTrace }
+	if errors.As(err, &tracer) {
+		info.Stack = strings.TrimPrefix(fmt.Sprintf("%+v", tracer.StackTrace()), "\n")
+	}
+
+	return info
 }
 
 // getRequestID 获取请求ID，如果不存在则生成新的
@@ -157,7 +380,12 @@ func getRequestID(c *gin.Context) string {
 
 // getTraceIDFromContext 从上下文中获取链路追踪ID
 func getTraceIDFromContext(c *gin.Context) string {
-	// 先从上下文中获取
+	// 优先使用当前 OpenTelemetry Span 的追踪ID
+	if spanCtx := trace.SpanContextFromContext(c.Request.Context()); spanCtx.IsValid() {
+		return spanCtx.TraceID().String()
+	}
+
+	// 再从上下文中获取
 	if traceID, exists := c.Get("trace_id"); exists {
 		if strID, ok := traceID.(string); ok && strID != "" {
 			return strID
@@ -0,0 +1,42 @@
+package response
+
+import "sync"
+
+// StatusMapper 错误到HTTP状态码的映射函数，返回 false 表示不处理该错误
+type StatusMapper func(err error) (int, bool)
+
+var (
+	statusMappers   []StatusMapper
+	statusMappersMu sync.RWMutex
+)
+
+// RegisterStatusMapper 注册错误到HTTP状态码的映射
+// 映射按注册顺序优先于错误自身的 HttpStatus() 生效，第一个匹配的映射胜出
+func RegisterStatusMapper(mapper StatusMapper) {
+	statusMappersMu.Lock()
+	defer statusMappersMu.Unlock()
+
+	statusMappers = append(statusMappers, mapper)
+}
+
+// ResetStatusMappers 移除所有已注册的状态码映射，主要用于测试
+func ResetStatusMappers() {
+	statusMappersMu.Lock()
+	defer statusMappersMu.Unlock()
+
+	statusMappers = nil
+}
+
+// mapStatus 按注册顺序查找错误对应的HTTP状态码
+func mapStatus(err error) (int, bool) {
+	statusMappersMu.RLock()
+	defer statusMappersMu.RUnlock()
+
+	for _, mapper := range statusMappers {
+		if status, ok := mapper(err); ok {
+			return status, true
+		}
+	}
+
+	return 0, false
+}
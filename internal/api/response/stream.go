@@ -0,0 +1,104 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/pkg/logger"
+)
+
+// defaultHeartbeat 默认心跳间隔
+const defaultHeartbeat = 15 * time.Second
+
+// StreamOption 流式响应选项
+type StreamOption func(*streamOptions)
+
+// streamOptions 流式响应配置
+type streamOptions struct {
+	heartbeat time.Duration // 心跳间隔，小于等于0时不发送心跳
+}
+
+// WithHeartbeat 设置心跳间隔，防止代理因长时间无数据断开连接
+func WithHeartbeat(interval time.Duration) StreamOption {
+	return func(o *streamOptions) {
+		o.heartbeat = interval
+	}
+}
+
+// Stream 以 Server-Sent Events 的形式推送数据
+// 每个值编码为一条 data 消息并立即刷新，通道关闭或客户端断开时结束
+func Stream(c *gin.Context, ch <-chan any, opts ...StreamOption) {
+	o := streamOptions{heartbeat: defaultHeartbeat}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // 禁用 nginx 缓冲
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	var heartbeat <-chan time.Time
+	if o.heartbeat > 0 {
+		ticker := time.NewTicker(o.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case v, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := writeEvent(c.Writer, v); err != nil {
+				logger.WarnContext(ctx, "Failed to write stream event", "error", err)
+				return
+			}
+			c.Writer.Flush()
+		case <-heartbeat:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeEvent 写入一条 SSE 消息，字符串原样输出，其他值编码为JSON
+func writeEvent(w io.Writer, v any) error {
+	var data string
+	switch val := v.(type) {
+	case string:
+		data = val
+	case []byte:
+		data = string(val)
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+
+	// 多行数据需拆分为多个 data 字段
+	var sb strings.Builder
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,110 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// TimeFormat 响应时间戳的格式
+type TimeFormat int
+
+const (
+	UnixSeconds TimeFormat = iota // Unix 秒级时间戳，默认值
+	UnixMillis                    // Unix 毫秒级时间戳
+	RFC3339                       // RFC3339 字符串，精确到毫秒
+)
+
+// rfc3339Millis 精确到毫秒的 RFC3339 格式
+const rfc3339Millis = "2006-01-02T15:04:05.000Z07:00"
+
+// WithTimeFormat 设置响应中 timestamp 字段的格式，默认为 UnixSeconds
+func WithTimeFormat(format TimeFormat) Option {
+	return func(c *config) {
+		c.timeFormat = format
+	}
+}
+
+// timestamped 为响应设置当前时间
+func timestamped[T any](r Result[T]) Result[T] {
+	at := time.Now()
+	r.Time = at.Unix()
+	r.at = at
+	return r
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，按配置的格式输出 timestamp 字段
+// Time 字段始终为 Unix 秒级时间戳，毫秒和 RFC3339 格式使用创建响应时记录的精确时间
+func (r Result[T]) MarshalJSON() ([]byte, error) {
+	type plain Result[T]
+
+	format := getConfig().timeFormat
+	if format == UnixSeconds || r.Time == 0 {
+		return json.Marshal(plain(r))
+	}
+
+	at := r.at
+	if at.IsZero() {
+		at = time.Unix(r.Time, 0)
+	}
+
+	var timestamp any = at.UnixMilli()
+	if format == RFC3339 {
+		timestamp = at.Format(rfc3339Millis)
+	}
+
+	return json.Marshal(struct {
+		plain
+		Time any `json:"timestamp"`
+	}{plain(r), timestamp})
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口
+// timestamp 为字符串时按 RFC3339 解析；为数字且超过 1e12 时视为毫秒，否则视为秒
+func (r *Result[T]) UnmarshalJSON(data []byte) error {
+	type plain Result[T]
+
+	aux := struct {
+		*plain
+		Time json.RawMessage `json:"timestamp"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	at, err := parseTimestamp(aux.Time)
+	if err != nil {
+		return err
+	}
+	r.at = at
+	r.Time = 0
+	if !at.IsZero() {
+		r.Time = at.Unix()
+	}
+	return nil
+}
+
+// parseTimestamp 解析各种格式的时间戳，为空或 null 时返回零值
+func parseTimestamp(data json.RawMessage) (time.Time, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return time.Time{}, nil
+	}
+
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return time.Time{}, err
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	}
+
+	n, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if n > 1e12 {
+		return time.UnixMilli(n), nil
+	}
+	return time.Unix(n, 0), nil
+}
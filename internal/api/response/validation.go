@@ -0,0 +1,122 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/limitcool/starter/internal/errspec"
+)
+
+// ValidationDetails 将校验错误转换为 字段→消息 的映射，字段名使用蛇形命名
+// 支持 validator.ValidationErrors 和 errspec.NewValidationError 创建的错误，都不包含时返回 false
+func ValidationDetails(err error) (map[string]string, bool) {
+	var fieldErrs errspec.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		return fieldErrs, true
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil, false
+	}
+
+	details := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		field := fieldName(fe)
+		details[field] = fieldMessage(field, fe)
+	}
+
+	return details, true
+}
+
+// validationMessage 生成校验失败的消息，列出校验失败的字段
+func validationMessage(c *gin.Context, details map[string]string) string {
+	fields := make([]string, 0, len(details))
+	for field := range details {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return errspec.ErrInvalidParams.New(c.Request.Context(), struct{ Params string }{strings.Join(fields, ", ")}).Error()
+}
+
+// fieldName 获取字段路径，去掉顶层结构体名，如 Req.Address.ZipCode 转为 address.zip_code
+func fieldName(fe validator.FieldError) string {
+	parts := strings.Split(fe.Namespace(), ".")
+	if len(parts) > 1 {
+		parts = parts[1:]
+	}
+	for i, part := range parts {
+		parts[i] = toSnakeCase(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// fieldMessage 生成易读的字段错误消息
+func fieldMessage(field string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return fmt.Sprintf("%s is required", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "url", "uri":
+		return fmt.Sprintf("%s must be a valid URL", field)
+	case "min", "gte":
+		if isLengthKind(fe) {
+			return fmt.Sprintf("%s must be at least %s characters long", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "max", "lte":
+		if isLengthKind(fe) {
+			return fmt.Sprintf("%s must be at most %s characters long", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", field, fe.Param())
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s characters long", field, fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", field, fe.Param())
+	case "numeric", "number":
+		return fmt.Sprintf("%s must be a number", field)
+	case "eqfield":
+		return fmt.Sprintf("%s must match %s", field, toSnakeCase(fe.Param()))
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
+
+// isLengthKind 判断字段是否按长度校验
+func isLengthKind(fe validator.FieldError) bool {
+	switch fe.Kind().String() {
+	case "string", "slice", "map", "array":
+		return true
+	default:
+		return false
+	}
+}
+
+// toSnakeCase 将驼峰命名转换为蛇形命名，如 UserID 转为 user_id
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var sb strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
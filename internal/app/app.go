@@ -207,6 +207,7 @@ func (a *App) initStorage() error {
 func (a *App) initRouter() error {
 	r, err := newRouter(
 		a.config,
+		a.db,
 		handler.NewUserHandler(a),
 		handler.NewFileHandler(a),
 		handler.NewAdminHandler(a),
@@ -5,26 +5,42 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"time"
 
 	"github.com/epkgs/i18n"
 	"github.com/gin-gonic/gin"
 	"github.com/limitcool/starter/configs"
+	"github.com/limitcool/starter/internal/api/response"
 	"github.com/limitcool/starter/internal/dto"
 	"github.com/limitcool/starter/internal/handler"
 	"github.com/limitcool/starter/internal/middleware"
+	"github.com/limitcool/starter/internal/model"
 	"github.com/limitcool/starter/internal/pkg/logger"
+	"gorm.io/gorm"
 )
 
+// healthCheckTimeout 健康检查探测数据库的超时时间
+const healthCheckTimeout = 3 * time.Second
+
 // newRouter 创建路由器（不依赖fx）
-func newRouter(config *configs.Config, handlers ...handler.RouterInitializer) (*gin.Engine, error) {
+// db 为 nil 时表示未启用数据库，健康检查不探测数据库
+func newRouter(config *configs.Config, db *gorm.DB, handlers ...handler.RouterInitializer) (*gin.Engine, error) {
 	// 设置Gin模式
 	gin.SetMode(config.App.Mode)
 
+	// 错误响应是否返回调试信息，生产环境隐藏未定义错误的原始消息
+	response.Configure(
+		response.WithDebug(config.App.DebugResponse),
+		response.WithSanitizeErrors(config.App.Mode == gin.ReleaseMode),
+	)
+
 	// 创建路由器
 	r := gin.New()
 
 	// 添加中间件
-	r.Use(middleware.RequestLoggerMiddleware())
+	r.Use(middleware.RequestID())
+	r.Use(middleware.RequestLoggerMiddleware(middleware.WithSkipPaths("/health")))
+	r.Use(middleware.ContextLogger())
 	r.Use(middleware.Cors())
 
 	// 添加国际化中间件
@@ -36,8 +52,18 @@ func newRouter(config *configs.Config, handlers ...handler.RouterInitializer) (*
 	r.Use(middleware.PanicRecovery())
 	r.Use(middleware.GlobalErrorHandler())
 
-	// 健康检查
+	// 健康检查，数据库不可用时返回503
 	r.GET("/health", func(c *gin.Context) {
+		if db != nil {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+			defer cancel()
+
+			if err := model.PingDB(ctx, db); err != nil {
+				response.Error(c, err)
+				return
+			}
+		}
+
 		c.JSON(200, &dto.HealthResponse{
 			Status: "ok",
 		})
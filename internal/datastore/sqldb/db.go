@@ -12,6 +12,7 @@ import (
 	"gorm.io/driver/postgres"
 	gormlogger "gorm.io/gorm/logger"
 	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -66,7 +67,7 @@ func gormConfig(c *configs.Config) *gorm.Config {
 		// 使用我们的结构化日志
 		&gormLogWriter{},
 		gormlogger.Config{
-			SlowThreshold: c.Database.SlowThreshold,
+			SlowThreshold: 0,     // 慢查询由 SlowQueryPlugin 以结构化字段记录
 			Colorful:      false, // 禁用颜色以避免与结构化日志冲突
 			LogLevel:      getGormLogLevel(c),
 		},
@@ -154,6 +155,32 @@ func newDbConn(c *configs.Config) *gorm.DB {
 			"database", c.Database.DBName,
 			"error", err)
 	}
+
+	// 配置了只读副本时启用读写分离
+	if len(c.Database.Replicas) > 0 {
+		replicas := make([]gorm.Dialector, 0, len(c.Database.Replicas))
+		for _, dsn := range c.Database.Replicas {
+			replicas = append(replicas, openDialector(c.Driver, dsn))
+		}
+		if err := db.Use(dbresolver.Register(dbresolver.Config{Replicas: replicas})); err != nil {
+			logger.FatalContext(context.Background(), "Failed to register database replicas", "error", err)
+		}
+	}
+
+	// 记录慢查询，支持按表设置阈值
+	if c.Database.SlowThreshold > 0 || len(c.Database.SlowThresholds) > 0 {
+		if err := db.Use(NewSlowQueryPlugin(c.Database.SlowThreshold, c.Database.SlowThresholds)); err != nil {
+			logger.WarnContext(context.Background(), "Failed to register slow query plugin", "error", err)
+		}
+	}
+
+	// 慢查询时记录执行计划
+	if c.Database.ExplainSlowQuery && (c.Database.SlowThreshold > 0 || len(c.Database.SlowThresholds) > 0) {
+		if err := db.Use(NewExplainPlugin(c.Database.SlowThreshold, c.Database.SlowThresholds)); err != nil {
+			logger.WarnContext(context.Background(), "Failed to register explain plugin", "error", err)
+		}
+	}
+
 	db.Set("gorm:table_options", "CHARSET=utf8mb4")
 	err = db.AutoMigrate()
 	if err != nil {
@@ -163,15 +190,20 @@ func newDbConn(c *configs.Config) *gorm.DB {
 }
 
 func getGormDriver(c *configs.Config) gorm.Dialector {
-	switch c.Driver {
+	return openDialector(c.Driver, getDSN(c))
+}
+
+// openDialector 根据驱动类型和DSN创建GORM方言
+func openDialector(driver configs.DBDriver, dsn string) gorm.Dialector {
+	switch driver {
 	case configs.DriverMysql:
-		return mysql.Open(getDSN(c))
+		return mysql.Open(dsn)
 	case configs.DriverPostgres:
-		return postgres.Open(getDSN(c))
+		return postgres.Open(dsn)
 	case configs.DriverSqlite:
-		return sqlite.Open(getDSN(c))
+		return sqlite.Open(dsn)
 	default:
-		logger.FatalContext(context.Background(), "Unsupported database driver", "driver", c.Driver)
+		logger.FatalContext(context.Background(), "Unsupported database driver", "driver", driver)
 		return nil
 	}
 }
@@ -0,0 +1,143 @@
+package sqldb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// explainStartKey 记录查询开始时间的实例键
+const explainStartKey = "starter:explain_start"
+
+// ExplainPlugin 慢查询执行计划插件
+// 查询耗时超过阈值时，使用 EXPLAIN 重新执行该查询并以 warn 级别记录执行计划。
+// EXPLAIN 会带来额外的数据库开销，仅建议在非生产环境开启。
+type ExplainPlugin struct {
+	SlowThreshold   time.Duration            // 慢查询阈值，小于等于 0 时只检查 TableThresholds 中的表
+	TableThresholds map[string]time.Duration // 按表名覆盖慢查询阈值，与 SlowQueryPlugin 相同
+}
+
+// NewExplainPlugin 创建慢查询执行计划插件
+func NewExplainPlugin(slowThreshold time.Duration, tableThresholds map[string]time.Duration) *ExplainPlugin {
+	return &ExplainPlugin{
+		SlowThreshold:   slowThreshold,
+		TableThresholds: tableThresholds,
+	}
+}
+
+// Name 实现 gorm.Plugin 接口
+func (p *ExplainPlugin) Name() string {
+	return "starter:explain"
+}
+
+// Initialize 实现 gorm.Plugin 接口
+// 只注册查询回调，写操作不会被 EXPLAIN
+func (p *ExplainPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register("starter:explain_before", p.before); err != nil {
+		return err
+	}
+	return db.Callback().Query().After("gorm:query").Register("starter:explain_after", p.after)
+}
+
+// before 记录查询开始时间
+func (p *ExplainPlugin) before(db *gorm.DB) {
+	db.InstanceSet(explainStartKey, time.Now())
+}
+
+// after 查询超过阈值时记录执行计划
+func (p *ExplainPlugin) after(db *gorm.DB) {
+	if db.Error != nil || db.DryRun {
+		return
+	}
+
+	v, ok := db.InstanceGet(explainStartKey)
+	if !ok {
+		return
+	}
+	start, ok := v.(time.Time)
+	if !ok {
+		return
+	}
+
+	threshold := slowThresholdFor(p.SlowThreshold, p.TableThresholds, db.Statement.Table)
+	elapsed := time.Since(start)
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+
+	query := db.Statement.SQL.String()
+	if !isSelectQuery(query) {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	plan, err := explainQuery(ctx, db, query, db.Statement.Vars)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to explain slow query", "sql", query, "error", err)
+		return
+	}
+
+	logger.WarnContext(ctx, "Slow query plan",
+		"sql", query,
+		"elapsed_ms", elapsed.Milliseconds(),
+		"plan", plan,
+	)
+}
+
+// isSelectQuery 判断是否为只读的 SELECT 语句
+func isSelectQuery(query string) bool {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	return strings.HasPrefix(query, "SELECT") || strings.HasPrefix(query, "WITH")
+}
+
+// explainQuery 执行 EXPLAIN 并将结果格式化为多行文本
+func explainQuery(ctx context.Context, db *gorm.DB, query string, vars []any) (string, error) {
+	prefix := "EXPLAIN "
+	if db.Dialector.Name() == "sqlite" {
+		prefix = "EXPLAIN QUERY PLAN "
+	}
+
+	rows, err := db.Statement.ConnPool.QueryContext(ctx, prefix+query, vars...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return "", err
+		}
+
+		fields := make([]string, 0, len(columns))
+		for i, column := range columns {
+			value := values[i]
+			if b, ok := value.([]byte); ok {
+				value = string(b)
+			}
+			fields = append(fields, fmt.Sprintf("%s=%v", column, value))
+		}
+		lines = append(lines, strings.Join(fields, " "))
+	}
+
+	return strings.Join(lines, "\n"), rows.Err()
+}
@@ -0,0 +1,121 @@
+package sqldb
+
+import (
+	"context"
+	"time"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// slowQueryStartKey 记录语句开始时间的实例键
+const slowQueryStartKey = "starter:slow_query_start"
+
+// SlowQueryPlugin 慢查询日志插件
+// 语句耗时超过阈值时，以 warn 级别记录 SQL、耗时、影响行数和表名。
+// 与 GORM 自带的慢查询日志不同，日志为结构化字段，并且可以按表单独设置阈值。
+type SlowQueryPlugin struct {
+	Threshold       time.Duration            // 默认阈值，小于等于 0 时只检查 TableThresholds 中的表
+	TableThresholds map[string]time.Duration // 按表名覆盖默认阈值，小于等于 0 表示不记录该表的慢查询
+}
+
+// NewSlowQueryPlugin 创建慢查询日志插件
+func NewSlowQueryPlugin(threshold time.Duration, tableThresholds map[string]time.Duration) *SlowQueryPlugin {
+	return &SlowQueryPlugin{
+		Threshold:       threshold,
+		TableThresholds: tableThresholds,
+	}
+}
+
+// Name 实现 gorm.Plugin 接口
+func (p *SlowQueryPlugin) Name() string {
+	return "starter:slow_query"
+}
+
+// Initialize 实现 gorm.Plugin 接口
+// 为查询、增删改和原生 SQL 语句注册回调
+func (p *SlowQueryPlugin) Initialize(db *gorm.DB) error {
+	type registerer interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}
+
+	callback := db.Callback()
+	pairs := [][2]registerer{
+		{callback.Query().Before("gorm:query"), callback.Query().After("gorm:query")},
+		{callback.Create().Before("gorm:create"), callback.Create().After("gorm:create")},
+		{callback.Update().Before("gorm:update"), callback.Update().After("gorm:update")},
+		{callback.Delete().Before("gorm:delete"), callback.Delete().After("gorm:delete")},
+		{callback.Row().Before("gorm:row"), callback.Row().After("gorm:row")},
+		{callback.Raw().Before("gorm:raw"), callback.Raw().After("gorm:raw")},
+	}
+
+	for _, pair := range pairs {
+		if err := pair[0].Register("starter:slow_query_before", p.before); err != nil {
+			return err
+		}
+		if err := pair[1].Register("starter:slow_query_after", p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before 记录语句开始时间
+func (p *SlowQueryPlugin) before(db *gorm.DB) {
+	db.InstanceSet(slowQueryStartKey, time.Now())
+}
+
+// after 语句超过阈值时记录日志
+func (p *SlowQueryPlugin) after(db *gorm.DB) {
+	if db.DryRun {
+		return
+	}
+
+	v, ok := db.InstanceGet(slowQueryStartKey)
+	if !ok {
+		return
+	}
+	start, ok := v.(time.Time)
+	if !ok {
+		return
+	}
+
+	table := db.Statement.Table
+	threshold := p.thresholdFor(table)
+	elapsed := time.Since(start)
+	if threshold <= 0 || elapsed < threshold {
+		return
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	keyvals := []any{
+		"sql", db.Dialector.Explain(db.Statement.SQL.String(), db.Statement.Vars...),
+		"elapsed_ms", elapsed.Milliseconds(),
+		"threshold_ms", threshold.Milliseconds(),
+		"rows", db.RowsAffected,
+		"table", table,
+	}
+	if db.Error != nil {
+		keyvals = append(keyvals, "error", db.Error)
+	}
+
+	logger.WarnContext(ctx, "Slow query", keyvals...)
+}
+
+// thresholdFor 获取表的慢查询阈值
+func (p *SlowQueryPlugin) thresholdFor(table string) time.Duration {
+	return slowThresholdFor(p.Threshold, p.TableThresholds, table)
+}
+
+// slowThresholdFor 获取表的慢查询阈值，表单独设置的阈值优先于默认阈值
+// SlowQueryPlugin 和 ExplainPlugin 共用，保证两者对同一语句的判断一致
+func slowThresholdFor(threshold time.Duration, tableThresholds map[string]time.Duration, table string) time.Duration {
+	if t, ok := tableThresholds[table]; ok {
+		return t
+	}
+	return threshold
+}
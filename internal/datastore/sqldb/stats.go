@@ -0,0 +1,24 @@
+package sqldb
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// RegisterDBStats 注册数据库连接池指标
+// 采集 sql.DBStats 中的最大/打开/使用中/空闲连接数、等待次数和等待耗时等指标，
+// 指标以 db_name 标签区分，多个数据库使用不同的 dbName 分别注册即可。
+// reg 为 nil 时使用 prometheus.DefaultRegisterer
+func RegisterDBStats(reg prometheus.Registerer, db *gorm.DB, dbName string) error {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return reg.Register(collectors.NewDBStatsCollector(sqlDB, dbName))
+}
@@ -17,16 +17,21 @@ var (
 	// 成功
 	Success = errorx.Define(commonI18n, 0, "success", http.StatusOK)
 
-	ErrUnknown         = errorx.Define(commonI18n, 5000, "unknown error", http.StatusInternalServerError)                                    // 未知错误
-	ErrInvalidParams   = errorx.Definef[struct{ Params string }](commonI18n, 1000, "invalid parameters: {{.Params}}", http.StatusBadRequest) // 请求参数错误
-	ErrInternal        = errorx.Define(commonI18n, 1001, "internal error", http.StatusInternalServerError)                                   // 服务器内部错误
-	ErrUnauthorized    = errorx.Define(commonI18n, 1002, "unauthorized", http.StatusUnauthorized)                                            // 未授权
-	ErrForbidden       = errorx.Define(commonI18n, 1003, "forbidden", http.StatusForbidden)                                                  // 禁止访问
-	ErrNotFound        = errorx.Define(commonI18n, 1004, "resource does not exist", http.StatusNotFound)                                     // 资源不存在
-	ErrTimeout         = errorx.Define(commonI18n, 1005, "request timeout", http.StatusRequestTimeout)                                       // 请求超时
-	ErrTooManyRequests = errorx.Define(commonI18n, 1006, "too many requests", http.StatusTooManyRequests)                                    // 请求过多
-	ErrAccessDenied    = errorx.Define(commonI18n, 1007, "access denied", http.StatusForbidden)                                              // 访问被拒绝
-	ErrUserAuthFailed  = errorx.Define(commonI18n, 1008, "user authentication failed", http.StatusUnauthorized)                              // 用户认证失败
-	ErrCasbinService   = errorx.Define(commonI18n, 1009, "casbin service error", http.StatusInternalServerError)                             // Casbin服务错误
-	ErrFileStorage     = errorx.Define(commonI18n, 1010, "file storage error", http.StatusInternalServerError)                               // 文件存储错误
+	ErrUnknown             = errorx.Define(commonI18n, 5000, "unknown error", http.StatusInternalServerError)                                    // 未知错误
+	ErrInvalidParams       = errorx.Definef[struct{ Params string }](commonI18n, 1000, "invalid parameters: {{.Params}}", http.StatusBadRequest) // 请求参数错误
+	ErrInternal            = errorx.Define(commonI18n, 1001, "internal error", http.StatusInternalServerError)                                   // 服务器内部错误
+	ErrUnauthorized        = errorx.Define(commonI18n, 1002, "unauthorized", http.StatusUnauthorized)                                            // 未授权
+	ErrForbidden           = errorx.Define(commonI18n, 1003, "forbidden", http.StatusForbidden)                                                  // 禁止访问
+	ErrNotFound            = errorx.Define(commonI18n, 1004, "resource does not exist", http.StatusNotFound)                                     // 资源不存在
+	ErrTimeout             = errorx.Define(commonI18n, 1005, "request timeout", http.StatusRequestTimeout)                                       // 请求超时
+	ErrTooManyRequests     = errorx.Define(commonI18n, 1006, "too many requests", http.StatusTooManyRequests)                                    // 请求过多
+	ErrAccessDenied        = errorx.Define(commonI18n, 1007, "access denied", http.StatusForbidden)                                              // 访问被拒绝
+	ErrUserAuthFailed      = errorx.Define(commonI18n, 1008, "user authentication failed", http.StatusUnauthorized)                              // 用户认证失败
+	ErrCasbinService       = errorx.Define(commonI18n, 1009, "casbin service error", http.StatusInternalServerError)                             // Casbin服务错误
+	ErrFileStorage         = errorx.Define(commonI18n, 1010, "file storage error", http.StatusInternalServerError)                               // 文件存储错误
+	ErrBatchPartialSuccess = errorx.Define(commonI18n, 1011, "batch partially succeeded", http.StatusMultiStatus)                                // 批量操作部分成功
+	ErrBatchFailed         = errorx.Define(commonI18n, 1012, "batch failed", http.StatusMultiStatus)                                             // 批量操作全部失败
+	ErrRequestCanceled     = errorx.Define(commonI18n, 1013, "request canceled", http.StatusRequestTimeout)                                      // 请求已取消
+	ErrValidation          = errorx.Define(commonI18n, 1014, "validation failed", http.StatusUnprocessableEntity)                                // 参数校验失败
+	ErrIdempotencyConflict = errorx.Define(commonI18n, 1015, "request with the same idempotency key is in progress", http.StatusConflict)        // 相同幂等键的请求正在处理
 )
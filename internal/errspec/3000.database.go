@@ -14,23 +14,38 @@ func init() {
 var dbI18n = i18n.NewCatalog("database")
 
 var (
-	ErrDatabase            = errorx.Define(dbI18n, 3000, "database error", http.StatusInternalServerError)             // 数据库错误
-	ErrDatabaseQuery       = errorx.Define(dbI18n, 3001, "database query error", http.StatusInternalServerError)       // 数据库查询错误
-	ErrDatabaseInsert      = errorx.Define(dbI18n, 3002, "database insert error", http.StatusInternalServerError)      // 数据库插入错误
-	ErrDatabaseUpdate      = errorx.Define(dbI18n, 3003, "database update error", http.StatusInternalServerError)      // 数据库更新错误
-	ErrDatabaseDelete      = errorx.Define(dbI18n, 3004, "database delete error", http.StatusInternalServerError)      // 数据库删除错误
-	ErrDatabaseConnection  = errorx.Define(dbI18n, 3005, "database connection error", http.StatusInternalServerError)  // 数据库连接错误
-	ErrDatabaseTransaction = errorx.Define(dbI18n, 3006, "database transaction error", http.StatusInternalServerError) // 数据库事务错误
-	ErrQueryParamEmpty     = errorx.Define(dbI18n, 3007, "query parameter cannot be empty", http.StatusBadRequest)     // 查询参数不能为空
-	ErrRecordNotExist      = errorx.Define(dbI18n, 3008, "record does not exist", http.StatusNotFound)                 // 记录不存在
-	ErrQueryUser           = errorx.Define(dbI18n, 3009, "query user failed", http.StatusInternalServerError)          // 查询用户失败
-	ErrQueryUserAvatar     = errorx.Define(dbI18n, 3010, "query user avatar failed", http.StatusInternalServerError)   // 查询用户头像失败
-	ErrCheckUserExist      = errorx.Define(dbI18n, 3011, "check user exist failed", http.StatusInternalServerError)    // 检查用户是否存在失败
-	ErrQueryUserList       = errorx.Define(dbI18n, 3012, "query user list failed", http.StatusInternalServerError)     // 查询用户列表失败
-	ErrQueryUserTotal      = errorx.Define(dbI18n, 3013, "query user total failed", http.StatusInternalServerError)    // 查询用户总数失败
-	ErrQueryFile           = errorx.Define(dbI18n, 3014, "query file failed", http.StatusBadRequest)                   // 查询文件失败
-	ErrQueryUserFileList   = errorx.Define(dbI18n, 3015, "query user file list failed", http.StatusBadRequest)         // 查询用户文件列表失败
-	ErrQueryUserFileTotal  = errorx.Define(dbI18n, 3016, "query user file total failed", http.StatusBadRequest)        // 查询用户文件总数失败
-	ErrQueryFileList       = errorx.Define(dbI18n, 3017, "query file list failed", http.StatusBadRequest)              // 查询文件列表失败
-	ErrQueryFileTotal      = errorx.Define(dbI18n, 3018, "query file total failed", http.StatusBadRequest)             // 查询文件总数失败
+	ErrDatabase               = errorx.Define(dbI18n, 3000, "database error", http.StatusInternalServerError)                              // 数据库错误
+	ErrDatabaseQuery          = errorx.Define(dbI18n, 3001, "database query error", http.StatusInternalServerError)                        // 数据库查询错误
+	ErrDatabaseInsert         = errorx.Define(dbI18n, 3002, "database insert error", http.StatusInternalServerError)                       // 数据库插入错误
+	ErrDatabaseUpdate         = errorx.Define(dbI18n, 3003, "database update error", http.StatusInternalServerError)                       // 数据库更新错误
+	ErrDatabaseDelete         = errorx.Define(dbI18n, 3004, "database delete error", http.StatusInternalServerError)                       // 数据库删除错误
+	ErrDatabaseConnection     = errorx.Define(dbI18n, 3005, "database connection error", http.StatusInternalServerError)                   // 数据库连接错误
+	ErrDatabaseTransaction    = errorx.Define(dbI18n, 3006, "database transaction error", http.StatusInternalServerError)                  // 数据库事务错误
+	ErrQueryParamEmpty        = errorx.Define(dbI18n, 3007, "query parameter cannot be empty", http.StatusBadRequest)                      // 查询参数不能为空
+	ErrRecordNotExist         = errorx.Define(dbI18n, 3008, "record does not exist", http.StatusNotFound)                                  // 记录不存在
+	ErrQueryUser              = errorx.Define(dbI18n, 3009, "query user failed", http.StatusInternalServerError)                           // 查询用户失败
+	ErrQueryUserAvatar        = errorx.Define(dbI18n, 3010, "query user avatar failed", http.StatusInternalServerError)                    // 查询用户头像失败
+	ErrCheckUserExist         = errorx.Define(dbI18n, 3011, "check user exist failed", http.StatusInternalServerError)                     // 检查用户是否存在失败
+	ErrQueryUserList          = errorx.Define(dbI18n, 3012, "query user list failed", http.StatusInternalServerError)                      // 查询用户列表失败
+	ErrQueryUserTotal         = errorx.Define(dbI18n, 3013, "query user total failed", http.StatusInternalServerError)                     // 查询用户总数失败
+	ErrQueryFile              = errorx.Define(dbI18n, 3014, "query file failed", http.StatusBadRequest)                                    // 查询文件失败
+	ErrQueryUserFileList      = errorx.Define(dbI18n, 3015, "query user file list failed", http.StatusBadRequest)                          // 查询用户文件列表失败
+	ErrQueryUserFileTotal     = errorx.Define(dbI18n, 3016, "query user file total failed", http.StatusBadRequest)                         // 查询用户文件总数失败
+	ErrQueryFileList          = errorx.Define(dbI18n, 3017, "query file list failed", http.StatusBadRequest)                               // 查询文件列表失败
+	ErrQueryFileTotal         = errorx.Define(dbI18n, 3018, "query file total failed", http.StatusBadRequest)                              // 查询文件总数失败
+	ErrMultipleRows           = errorx.Define(dbI18n, 3019, "multiple records found", http.StatusConflict)                                 // 匹配到多条记录
+	ErrSoftDeleteNotSupported = errorx.Define(dbI18n, 3020, "soft delete is not supported", http.StatusInternalServerError)                // 实体不支持软删除
+	ErrNotInTransaction       = errorx.Define(dbI18n, 3021, "operation must run in a transaction", http.StatusInternalServerError)         // 操作必须在事务中执行
+	ErrTooManyPreloads        = errorx.Define(dbI18n, 3022, "too many preloads", http.StatusBadRequest)                                    // 预加载关联过多或嵌套过深
+	ErrInvalidColumn          = errorx.Define(dbI18n, 3023, "invalid column", http.StatusBadRequest)                                       // 无效的列名
+	ErrDistinctOnNotSupported = errorx.Define(dbI18n, 3024, "distinct on is only supported by postgres", http.StatusInternalServerError)   // DISTINCT ON 仅支持 Postgres
+	ErrDatabaseUnavailable    = errorx.Define(dbI18n, 3025, "database unavailable", http.StatusServiceUnavailable)                         // 数据库不可用
+	ErrInvalidCursor          = errorx.Define(dbI18n, 3026, "invalid cursor", http.StatusBadRequest)                                       // 无效的分页游标
+	ErrInvalidRelation        = errorx.Define(dbI18n, 3027, "invalid relation", http.StatusBadRequest)                                     // 无效的关联
+	ErrDatabaseNotRegistered  = errorx.Define(dbI18n, 3028, "database not registered", http.StatusInternalServerError)                     // 数据库未注册
+	ErrInvalidJSONPath        = errorx.Define(dbI18n, 3029, "invalid json path", http.StatusBadRequest)                                    // 无效的 JSON 路径
+	ErrJSONQueryNotSupported  = errorx.Define(dbI18n, 3030, "json query is not supported by the database", http.StatusInternalServerError) // 数据库不支持 JSON 查询
+	ErrInvalidTimeRange       = errorx.Define(dbI18n, 3031, "invalid time range", http.StatusBadRequest)                                   // 无效的时间范围
+	ErrInvalidJSONOperator    = errorx.Define(dbI18n, 3032, "invalid json operator", http.StatusBadRequest)                                // 无效的 JSON 查询操作符
+	ErrInvalidJSONValue       = errorx.Define(dbI18n, 3033, "invalid json value", http.StatusBadRequest)                                   // 无法编码为 JSON 的查询值
 )
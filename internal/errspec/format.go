@@ -0,0 +1,15 @@
+package errspec
+
+import (
+	"context"
+
+	i18nerrx "github.com/epkgs/i18n/errorx"
+	"github.com/limitcool/starter/internal/pkg/errorx"
+)
+
+// Newf 创建错误并使用格式化的消息，错误码和HTTP状态码与错误定义相同
+// 如 errspec.Newf(ctx, errspec.ErrRecordNotExist, "user %d not found", id)。
+// 格式化的消息会原样返回给客户端，不再按请求语言翻译。
+func Newf(ctx context.Context, def *i18nerrx.DefinitionSimple[*errorx.AppError], format string, args ...any) *errorx.AppError {
+	return def.New(ctx).WithMessagef(format, args...)
+}
@@ -0,0 +1,14 @@
+package errspec
+
+import "github.com/limitcool/starter/internal/pkg/errorx"
+
+// Lookup 按错误码查找错误定义，包括默认消息和HTTP状态码
+// 本包中的错误在定义时自动注册，业务代码通过 errorx.Define 定义的错误同样可以查询
+func Lookup(code int) (errorx.Spec, bool) {
+	return errorx.LookupSpec(code)
+}
+
+// All 获取所有已注册的错误定义，按错误码排序，可用于生成 API 错误码文档
+func All() []errorx.Spec {
+	return errorx.Specs()
+}
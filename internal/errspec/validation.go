@@ -0,0 +1,33 @@
+package errspec
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/limitcool/starter/internal/pkg/errorx"
+)
+
+// FieldErrors 字段级的校验错误，key 为字段名，value 为错误消息
+type FieldErrors map[string]string
+
+// Error 实现 error 接口，按字段名排序输出
+func (f FieldErrors) Error() string {
+	fields := make([]string, 0, len(f))
+	for field := range f {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field+": "+f[field])
+	}
+	return strings.Join(parts, "; ")
+}
+
+// NewValidationError 创建携带字段错误的校验错误
+// 字段错误保存在错误链中，response.Error 会将其作为 details 返回
+func NewValidationError(ctx context.Context, fields map[string]string) *errorx.AppError {
+	return ErrValidation.New(ctx).Wrap(FieldErrors(fields))
+}
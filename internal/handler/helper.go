@@ -38,7 +38,7 @@ func (h *HandlerHelper) BindJSON(ctx *gin.Context, req interface{}, operation st
 		logger.WarnContext(reqCtx, operation+" request validation failed",
 			"error", err,
 			"client_ip", ctx.ClientIP())
-		response.Error(ctx, errspec.ErrInvalidParams.New(ctx, struct{ Params string }{err.Error()}))
+		response.Error(ctx, errspec.ErrInvalidParams.New(ctx, struct{ Params string }{err.Error()}).Wrap(err))
 		return false
 	}
 
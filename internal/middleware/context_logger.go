@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/pkg/logger"
+)
+
+// ContextLogger 请求级日志记录器中间件
+// 为每个请求创建携带请求方法、路径、请求ID和链路追踪ID的日志记录器，
+// 处理器通过 logger.FromGin(c) 获取，服务层通过 logger.FromContext(ctx) 获取，
+// 无需在每次记录日志时重复添加请求信息。
+// 需注册在 RequestID 和 RequestLoggerMiddleware 之后，才能获取到请求ID和链路追踪ID。
+func ContextLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := map[string]any{
+			"method": c.Request.Method,
+			"path":   c.Request.URL.Path,
+		}
+		if requestID := c.GetString("request_id"); requestID != "" {
+			fields["request_id"] = requestID
+		}
+		if traceID := c.GetString("trace_id"); traceID != "" {
+			fields["trace_id"] = traceID
+		}
+
+		l := logger.Default().WithFields(fields)
+		logger.SetGin(c, l)
+		c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), l))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/api/response"
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/pkg/cache"
+	"github.com/limitcool/starter/internal/pkg/logger"
+)
+
+const (
+	// HeaderIdempotencyKey 幂等键请求头
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed 响应为重放结果时设置的响应头
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// DefaultIdempotencyTTL 幂等响应默认保存时长
+	DefaultIdempotencyTTL = 24 * time.Hour
+)
+
+// IdempotentResponse 保存的幂等响应
+type IdempotentResponse struct {
+	Status      int    `json:"status"`       // HTTP状态码
+	ContentType string `json:"content_type"` // 响应内容类型
+	Body        []byte `json:"body"`         // 响应体
+}
+
+// IdempotencyStore 幂等响应存储接口
+type IdempotencyStore interface {
+	// Get 获取已保存的响应，不存在时返回 nil
+	Get(ctx context.Context, key string) (*IdempotentResponse, error)
+
+	// Set 保存响应，ttl 后过期
+	Set(ctx context.Context, key string, resp *IdempotentResponse, ttl time.Duration) error
+}
+
+// cacheIdempotencyStore 基于缓存的幂等响应存储
+type cacheIdempotencyStore struct {
+	cache  cache.Cache
+	prefix string
+}
+
+// NewCacheIdempotencyStore 创建基于缓存的幂等响应存储，支持内存和 Redis 缓存
+func NewCacheIdempotencyStore(c cache.Cache) IdempotencyStore {
+	return &cacheIdempotencyStore{cache: c, prefix: "idempotency:"}
+}
+
+// Get 实现 IdempotencyStore 接口
+func (s *cacheIdempotencyStore) Get(ctx context.Context, key string) (*IdempotentResponse, error) {
+	data, err := s.cache.Get(ctx, s.prefix+key)
+	if err != nil {
+		if errors.Is(err, cache.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var resp IdempotentResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Set 实现 IdempotencyStore 接口
+func (s *cacheIdempotencyStore) Set(ctx context.Context, key string, resp *IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return s.cache.Set(ctx, s.prefix+key, data, ttl)
+}
+
+// idempotencyWriter 记录完整响应体的 ResponseWriter
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 写入响应时保留响应体
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString 写入响应时保留响应体
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency 幂等键中间件
+// 请求携带 Idempotency-Key 时，首次成功（2xx）的响应会被保存，ttl 内相同路由、
+// 相同方法、相同幂等键的请求直接重放保存的响应，不再执行处理器。
+// 同一幂等键的请求正在处理时返回 ErrIdempotencyConflict；失败的响应不会保存，客户端可以重试。
+// 未携带幂等键的请求不受影响。存储不可用时记录警告并正常处理请求。
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	var inFlight sync.Map
+
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(HeaderIdempotencyKey)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		key := c.Request.Method + " " + route + " " + idempotencyKey
+
+		if _, loaded := inFlight.LoadOrStore(key, struct{}{}); loaded {
+			response.Error(c, errspec.ErrIdempotencyConflict.New(ctx))
+			c.Abort()
+			return
+		}
+		defer inFlight.Delete(key)
+
+		stored, err := store.Get(ctx, key)
+		if err != nil {
+			logger.WarnContext(ctx, "Failed to load idempotent response", "key", key, "error", err)
+		}
+		if stored != nil {
+			c.Header(HeaderIdempotentReplayed, "true")
+			c.Data(stored.Status, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		status := writer.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return
+		}
+
+		resp := &IdempotentResponse{
+			Status:      status,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}
+		if err := store.Set(ctx, key, resp, ttl); err != nil {
+			logger.WarnContext(ctx, "Failed to save idempotent response", "key", key, "error", err)
+		}
+	}
+}
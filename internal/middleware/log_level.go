@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/pkg/logger"
+)
+
+const (
+	// HeaderLogLevel 指定当前请求日志级别的请求头
+	HeaderLogLevel = "X-Log-Level"
+	// HeaderLogLevelToken 调整请求日志级别时携带的令牌请求头
+	HeaderLogLevelToken = "X-Log-Level-Token"
+)
+
+// logLevelOptions 请求级日志级别选项
+type logLevelOptions struct {
+	networks []*net.IPNet // 允许调整级别的客户端网段
+	token    string       // 允许调整级别的令牌
+}
+
+// LogLevelOption 请求级日志级别选项
+type LogLevelOption func(*logLevelOptions)
+
+// WithLogLevelAllowedIPs 允许来自指定 IP 或网段（如 10.0.0.0/8）的请求调整日志级别
+// 无法解析的地址被忽略；客户端 IP 由 gin 的 ClientIP 获取，需正确配置可信代理
+func WithLogLevelAllowedIPs(addrs ...string) LogLevelOption {
+	return func(o *logLevelOptions) {
+		for _, addr := range addrs {
+			if !strings.Contains(addr, "/") {
+				if ip := net.ParseIP(addr); ip != nil && ip.To4() != nil {
+					addr += "/32"
+				} else {
+					addr += "/128"
+				}
+			}
+			if _, network, err := net.ParseCIDR(addr); err == nil {
+				o.networks = append(o.networks, network)
+			}
+		}
+	}
+}
+
+// WithLogLevelToken 允许携带指定令牌（X-Log-Level-Token 请求头）的请求调整日志级别
+func WithLogLevelToken(token string) LogLevelOption {
+	return func(o *logLevelOptions) {
+		o.token = token
+	}
+}
+
+// LogLevelOverride 按请求头调整单个请求的日志级别
+// 请求携带 X-Log-Level: debug 时，该请求通过上下文记录的日志使用 debug 级别，不影响其他请求，
+// 用于在生产环境排查单个请求。只有来自允许的 IP 或携带正确令牌的请求生效，
+// 未配置任何允许条件时忽略该请求头，无法识别的级别也被忽略。与 ContextLogger 的注册顺序无关。
+func LogLevelOverride(opts ...LogLevelOption) gin.HandlerFunc {
+	options := &logLevelOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return func(c *gin.Context) {
+		value := c.GetHeader(HeaderLogLevel)
+		if value == "" || !options.allowed(c) {
+			c.Next()
+			return
+		}
+
+		level, err := logger.ParseLevel(value)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		ctx := logger.NewLevelContext(c.Request.Context(), level)
+		c.Request = c.Request.WithContext(ctx)
+		logger.InfoContext(ctx, "Request log level overridden", "level", level.String(), "client_ip", c.ClientIP())
+
+		c.Next()
+	}
+}
+
+// allowed 判断请求是否允许调整日志级别
+func (o *logLevelOptions) allowed(c *gin.Context) bool {
+	if o.token != "" {
+		token := c.GetHeader(HeaderLogLevelToken)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(o.token)) == 1 {
+			return true
+		}
+	}
+
+	if len(o.networks) > 0 {
+		ip := net.ParseIP(c.ClientIP())
+		for _, network := range o.networks {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
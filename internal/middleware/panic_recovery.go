@@ -12,12 +12,14 @@ import (
 )
 
 // PanicRecovery 中间件用于捕获 panic 并返回友好的错误响应
-// 这个中间件只处理 panic，其他错误由 GlobalErrorHandler 处理
+// 这个中间件只处理 panic，其他错误由 GlobalErrorHandler 处理。
+// panic 的完整信息和堆栈只记录在服务端日志中，响应使用标准错误结构并返回 500，
+// 仅在 gin 的 debug 模式下才把 panic 信息返回给客户端。
 func PanicRecovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 使用defer+recover捕获所有可能的panic
 		defer func() {
-			if err := recover(); err != nil {
+			if r := recover(); r != nil {
 				// 记录堆栈信息
 				stack := string(debug.Stack())
 
@@ -28,23 +30,24 @@ func PanicRecovery() gin.HandlerFunc {
 				requestID, _ := c.Get("request_id")
 				traceID, _ := c.Get("trace_id")
 
-				logger.ErrorContext(ctx, "Panic recovered",
-					"error", err,
+				// 统一转换为 error
+				panicErr, ok := r.(error)
+				if !ok {
+					panicErr = fmt.Errorf("%v", r)
+				}
+
+				logger.LogErrorWithStackContext(ctx, "Panic recovered", panicErr,
 					"stack", stack,
 					"request_id", requestID,
 					"trace_id", traceID)
 
-				// 根据不同类型的panic返回不同的错误
-				var appErr *errorx.AppError
-				switch e := err.(type) {
-				case *errorx.AppError:
-					appErr = e
-				case error:
-					appErr = errspec.ErrInternal.New(ctx).Wrap(e)
-				case string:
-					appErr = errspec.ErrInternal.New(ctx).WithMessage(e)
-				default:
-					appErr = errspec.ErrInternal.New(ctx).WithMessage(fmt.Sprintf("%v", err))
+				// 主动以 AppError panic 的保留原错误，其他 panic 统一返回未知错误
+				appErr, ok := r.(*errorx.AppError)
+				if !ok {
+					appErr = errspec.ErrUnknown.New(ctx).Wrap(panicErr)
+					if gin.IsDebugging() {
+						appErr = appErr.WithMessage(panicErr.Error())
+					}
 				}
 
 				// 检查是否已经有响应写入，避免重复响应
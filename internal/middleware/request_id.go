@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID 请求ID的请求头和响应头名称
+const HeaderRequestID = "X-Request-ID"
+
+// RequestID 请求ID中间件
+// 优先复用上游网关传入的 X-Request-ID，没有则生成新的ID，
+// 并同时写入 gin 上下文、请求的 context.Context 和响应头
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(HeaderRequestID, requestID)
+
+		ctx := context.WithValue(c.Request.Context(), "request_id", requestID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -1,27 +1,103 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/limitcool/starter/internal/pkg/logger"
 )
 
+// defaultMaxLogBodySize 记录请求体和响应体的默认最大字节数
+const defaultMaxLogBodySize = 4096
+
+// requestLoggerOptions 请求日志选项
+type requestLoggerOptions struct {
+	skipPaths   map[string]struct{} // 不记录日志的路径
+	logBody     bool                // 是否记录请求体和响应体
+	maxBodySize int                 // 记录的最大字节数
+}
+
+// RequestLoggerOption 请求日志选项
+type RequestLoggerOption func(*requestLoggerOptions)
+
+// WithSkipPaths 设置不记录访问日志的路径，如健康检查
+func WithSkipPaths(paths ...string) RequestLoggerOption {
+	return func(o *requestLoggerOptions) {
+		for _, path := range paths {
+			o.skipPaths[path] = struct{}{}
+		}
+	}
+}
+
+// WithBodyLogging 记录请求体和响应体，超过 maxSize 字节的部分被截断
+// 请求体可能包含密码等敏感信息，仅建议在调试时开启
+func WithBodyLogging(maxSize int) RequestLoggerOption {
+	return func(o *requestLoggerOptions) {
+		o.logBody = true
+		o.maxBodySize = maxSize
+		if o.maxBodySize <= 0 {
+			o.maxBodySize = defaultMaxLogBodySize
+		}
+	}
+}
+
+// bodyLogWriter 记录响应体的 ResponseWriter
+type bodyLogWriter struct {
+	gin.ResponseWriter
+	body  *bytes.Buffer
+	limit int
+}
+
+// Write 写入响应时保留前 limit 个字节
+func (w *bodyLogWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString 写入响应时保留前 limit 个字节
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// capture 保存响应体，超过上限的部分丢弃
+func (w *bodyLogWriter) capture(b []byte) {
+	if remain := w.limit - w.body.Len(); remain > 0 {
+		if len(b) > remain {
+			b = b[:remain]
+		}
+		w.body.Write(b)
+	}
+}
+
 // RequestLoggerMiddleware 是一个记录请求日志的中间件，同时处理请求ID和链路追踪ID
-func RequestLoggerMiddleware() gin.HandlerFunc {
+// 延迟从进入中间件开始计算，到后续处理器全部完成为止
+func RequestLoggerMiddleware(opts ...RequestLoggerOption) gin.HandlerFunc {
+	options := &requestLoggerOptions{
+		skipPaths: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return func(c *gin.Context) {
 		// 记录开始时间
 		start := time.Now()
 
-		// 处理请求ID
-		requestID := c.GetHeader("X-Request-ID")
+		// 处理请求ID，优先使用 RequestID 中间件已设置的值
+		requestID := c.GetString("request_id")
+		if requestID == "" {
+			requestID = c.GetHeader(HeaderRequestID)
+		}
 		if requestID == "" {
 			requestID = fmt.Sprintf("req-%d", time.Now().UnixNano())
 		}
 		c.Set("request_id", requestID)
-		c.Header("X-Request-ID", requestID)
+		c.Header(HeaderRequestID, requestID)
 
 		// 处理链路追踪ID
 		traceID := c.GetHeader("X-Trace-ID")
@@ -36,6 +112,28 @@ func RequestLoggerMiddleware() gin.HandlerFunc {
 		ctx = context.WithValue(ctx, "trace_id", traceID)
 		c.Request = c.Request.WithContext(ctx)
 
+		// 跳过不需要记录的路径
+		if _, ok := options.skipPaths[c.Request.URL.Path]; ok {
+			c.Next()
+			return
+		}
+
+		// 只读取需要记录的前 maxBodySize 字节，再与剩余部分拼接后放回，
+		// 后续处理器仍能读到完整的请求体，大文件上传不会被整个缓冲到内存中
+		var requestBody []byte
+		var responseWriter *bodyLogWriter
+		if options.logBody {
+			if body := c.Request.Body; body != nil {
+				prefix, err := io.ReadAll(io.LimitReader(body, int64(options.maxBodySize)))
+				if err == nil {
+					c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(prefix), body), Closer: body}
+					requestBody = prefix
+				}
+			}
+			responseWriter = &bodyLogWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}, limit: options.maxBodySize}
+			c.Writer = responseWriter
+		}
+
 		// 处理请求
 		c.Next()
 
@@ -56,11 +154,20 @@ func RequestLoggerMiddleware() gin.HandlerFunc {
 			"status", status,
 			"latency_ms", latency.Milliseconds(),
 			"request_id", requestID,
+			"trace_id", traceID,
 			"user_agent", c.Request.UserAgent(),
 			"referer", c.Request.Referer(),
 			"body_size", c.Writer.Size(),
 		}
 
+		// 记录请求体和响应体
+		if options.logBody {
+			fields = append(fields,
+				"request_body", string(requestBody),
+				"response_body", responseWriter.body.String(),
+			)
+		}
+
 		// 如果有错误，记录错误信息
 		if len(c.Errors) > 0 {
 			fields = append(fields, "errors", c.Errors.String())
@@ -76,3 +183,9 @@ func RequestLoggerMiddleware() gin.HandlerFunc {
 		}
 	}
 }
+
+// readCloser 组合读取和关闭，用于放回部分读取过的请求体
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -121,4 +121,16 @@ func RegisterMigrations(migrator *Migrator) {
 			return tx.Where("username = ? AND is_admin = ?", username, true).Delete(&model.User{}).Error
 		},
 	})
+
+	// 添加审计日志表迁移
+	migrator.Register(&MigrationEntry{
+		Version: "202504080020",
+		Name:    "create_audit_log_table",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&model.AuditLog{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("audit_log")
+		},
+	})
 }
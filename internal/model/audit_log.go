@@ -0,0 +1,100 @@
+package model
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm"
+)
+
+// AuditLog 审计日志模型
+// 只追加不修改，因此没有更新时间和软删除字段
+type AuditLog struct {
+	ID         uint64    `json:"id" gorm:"primarykey"`
+	ActorID    string    `json:"actor_id" gorm:"size:64;index;comment:操作者ID"`
+	Action     string    `json:"action" gorm:"size:50;index;comment:操作类型"`
+	EntityType string    `json:"entity_type" gorm:"size:100;index:idx_audit_log_entity;comment:实体类型"`
+	EntityID   string    `json:"entity_id" gorm:"size:64;index:idx_audit_log_entity;comment:实体ID"`
+	Changes    string    `json:"changes" gorm:"type:text;comment:变更内容(JSON)"`
+	IP         string    `json:"ip" gorm:"size:50;comment:客户端IP"`
+	RequestID  string    `json:"request_id" gorm:"size:64;comment:请求ID"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index;comment:记录时间"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_log"
+}
+
+// AuditLogFilter 审计日志查询条件，零值字段不参与过滤
+type AuditLogFilter struct {
+	ActorID    string    // 操作者ID
+	Action     string    // 操作类型
+	EntityType string    // 实体类型
+	EntityID   string    // 实体ID，通常与 EntityType 一起使用
+	Since      time.Time // 起始时间（包含）
+	Until      time.Time // 结束时间（不包含）
+}
+
+// AuditLogRepo 审计日志仓库
+type AuditLogRepo struct {
+	*GenericRepo[AuditLog]
+}
+
+// NewAuditLogRepo 创建审计日志仓库
+func NewAuditLogRepo(db *gorm.DB) *AuditLogRepo {
+	return &AuditLogRepo{
+		GenericRepo: NewGenericRepo[AuditLog](db),
+	}
+}
+
+// ListAuditLogs 按条件分页查询审计日志，按记录时间倒序
+// 只接受 AuditLogFilter 中的过滤条件，值均以参数绑定，可以直接使用客户端传入的值；
+// cursor 为上一页返回的 NextCursor，首页传空字符串。Since 晚于 Until 时返回 ErrInvalidTimeRange。
+func (r *AuditLogRepo) ListAuditLogs(ctx context.Context, filter AuditLogFilter, cursor string, limit int) (*CursorResult[AuditLog], error) {
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Since.After(filter.Until) {
+		return nil, errspec.ErrInvalidTimeRange.New(ctx)
+	}
+
+	orders := []CursorOrder{{Column: "created_at", Desc: true}}
+	return r.ListByCursor(ctx, cursor, limit, orders, filter.queryOptions())
+}
+
+// queryOptions 将过滤条件转换为查询选项
+func (f AuditLogFilter) queryOptions() *QueryOptions {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, arg any) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+
+	if f.ActorID != "" {
+		add("actor_id = ?", f.ActorID)
+	}
+	if f.Action != "" {
+		add("action = ?", f.Action)
+	}
+	if f.EntityType != "" {
+		add("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != "" {
+		add("entity_id = ?", f.EntityID)
+	}
+	if !f.Since.IsZero() {
+		add("created_at >= ?", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("created_at < ?", f.Until)
+	}
+
+	if len(conditions) == 0 {
+		return nil
+	}
+	return &QueryOptions{
+		Condition: strings.Join(conditions, " AND "),
+		Args:      args,
+	}
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"context"
+	"errors"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultBatchSize 批量操作每批的默认条数
+const DefaultBatchSize = 100
+
+// BatchCreate 分批创建实体
+// 每批写入前检查 ctx，客户端断开或超时后立即停止，不再继续写入后续批次。
+// 已写入的批次不会回滚，需要全部成功或全部失败时应在事务中调用。
+func (r *GenericRepo[T]) BatchCreate(ctx context.Context, entities []T, batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	for start := 0; start < len(entities); start += batchSize {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		end := min(start+batchSize, len(entities))
+		batch := entities[start:end]
+		if err := r.withContext(ctx).Create(&batch).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BatchUpsert 在一个事务中分批插入或更新实体，适用于从外部数据源全量同步
+// conflictColumns 为判断记录是否已存在的唯一键列，冲突时更新 updateColumns 中的列，
+// updateColumns 为空时更新除主键外的所有列；需要刷新 updated_at 时应将其列入 updateColumns。
+// 任一批次失败或 ctx 取消时回滚整个事务。
+func (r *GenericRepo[T]) BatchUpsert(ctx context.Context, entities []T, conflictColumns, updateColumns []string, batchSize int) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	if len(conflictColumns) == 0 {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	sch, err := r.parseSchema()
+	if err != nil {
+		return err
+	}
+	for _, name := range append(append([]string{}, conflictColumns...), updateColumns...) {
+		if _, ok := sch.FieldsByDBName[name]; !ok {
+			return errspec.ErrInvalidColumn.New(ctx)
+		}
+	}
+
+	onConflict := clause.OnConflict{Columns: make([]clause.Column, 0, len(conflictColumns))}
+	for _, name := range conflictColumns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: name})
+	}
+	if len(updateColumns) > 0 {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
+	} else {
+		onConflict.UpdateAll = true
+	}
+
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		for start := 0; start < len(entities); start += batchSize {
+			if err := contextError(ctx); err != nil {
+				return err
+			}
+
+			end := min(start+batchSize, len(entities))
+			batch := entities[start:end]
+			if err := tx.Clauses(onConflict).Create(&batch).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Each 按主键升序分批读取实体并逐个调用 fn，内存中最多只保留一批记录，适用于导出、迁移等遍历大表的场景
+// 每批通过主键范围查询（WHERE id > 上一批最后的主键）获取，不使用 OFFSET，遍历越往后也不会变慢。
+// fn 返回错误或 ctx 取消时立即停止遍历并返回该错误；opts 中不应指定排序，否则会打乱按主键的分批。
+func (r *GenericRepo[T]) Each(ctx context.Context, batchSize int, opts *QueryOptions, fn func(T) error) error {
+	return r.EachBatch(ctx, batchSize, opts, func(batch []T) error {
+		for _, entity := range batch {
+			if err := fn(entity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EachBatch 按主键升序分批读取实体并逐批调用 fn，行为与 Each 相同
+// 每批使用新的切片，fn 可以保留传入的切片。
+func (r *GenericRepo[T]) EachBatch(ctx context.Context, batchSize int, opts *QueryOptions, fn func([]T) error) error {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	query := r.applyQueryOptions(r.withContext(ctx), opts)
+
+	// 只忽略查询返回的 ErrRecordNotFound，fn 返回的错误原样返回
+	var (
+		batch []T
+		fnErr error
+	)
+	err := query.FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+		fnErr = fn(batch)
+		return fnErr
+	}).Error
+	if fnErr != nil {
+		return err
+	}
+	return ignoreNotFound(err)
+}
+
+// contextError 检查上下文是否已取消或超时，并转换为对应的 errspec 错误
+func contextError(ctx context.Context) error {
+	err := ctx.Err()
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		return errspec.ErrTimeout.New(ctx).Wrap(err)
+	default:
+		return errspec.ErrRequestCanceled.New(ctx).Wrap(err)
+	}
+}
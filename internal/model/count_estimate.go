@@ -0,0 +1,45 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+)
+
+// EstimateCount 获取实体总数的估计值，适用于大表分页只需要近似总数的场景
+// Postgres 上没有过滤条件时读取 pg_class.reltuples 中统计信息的估计行数，不扫描表；
+// 有过滤条件、其他数据库或表尚未收集统计信息时回退为 Count 精确统计。
+// 估计值来自最近一次 ANALYZE / VACUUM，包含已软删除的记录，只应用于展示。
+func (r *GenericRepo[T]) EstimateCount(ctx context.Context, opts *QueryOptions) (int64, error) {
+	if r.DB.Dialector.Name() != "postgres" || hasFilters(opts) {
+		return r.Count(ctx, opts)
+	}
+
+	sch, err := r.parseSchema()
+	if err != nil {
+		return 0, err
+	}
+
+	// 从未 ANALYZE 的表 reltuples 为 -1（PG 14 之前为 0），此时统计信息不可用
+	var estimate sql.NullFloat64
+	err = r.withContext(ctx).
+		Raw("SELECT reltuples FROM pg_class WHERE oid = to_regclass(?)", sch.Table).
+		Row().Scan(&estimate)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	if !estimate.Valid || estimate.Float64 <= 0 {
+		return r.Count(ctx, opts)
+	}
+
+	return int64(estimate.Float64), nil
+}
+
+// hasFilters 判断查询选项是否会过滤或合并行，预加载、索引提示等不影响行数的选项不计入
+func hasFilters(opts *QueryOptions) bool {
+	if opts == nil {
+		return false
+	}
+	return opts.Condition != "" || len(opts.JSONConditions) > 0 || len(opts.Opts) > 0 ||
+		len(opts.Joins) > 0 || len(opts.GroupBy) > 0 || opts.Having != "" ||
+		opts.Distinct || len(opts.DistinctOn) > 0 || opts.OnlyDeleted
+}
@@ -0,0 +1,175 @@
+package model
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// CursorOrder 游标分页的排序列
+type CursorOrder struct {
+	Column string // 列名
+	Desc   bool   // 是否降序
+}
+
+// CursorResult 游标分页查询结果
+type CursorResult[T any] struct {
+	List       []T    // 当前页的数据
+	NextCursor string // 下一页的游标，没有更多数据时为空
+}
+
+// ListByCursor 按游标分页获取实体列表（键集分页）
+// orders 为排序列，可以由多列组成，如 created_at 降序；排序列中没有主键时自动追加主键，
+// 方向与最后一个排序列相同，保证排序值相同的记录也有确定的先后顺序，不会跨页重复或遗漏。
+// cursor 为上一页返回的 NextCursor，首页传空字符串；游标中包含所有排序列的值，
+// 排序列与生成游标时不一致或游标被篡改时返回 ErrInvalidCursor。
+func (r *GenericRepo[T]) ListByCursor(ctx context.Context, cursor string, limit int, orders []CursorOrder, opts *QueryOptions) (*CursorResult[T], error) {
+	sch, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, orders, err := cursorFields(ctx, sch, orders)
+	if err != nil {
+		return nil, err
+	}
+
+	_, limit = r.normalizePage(1, limit)
+
+	query := r.applyQueryOptions(r.withContext(ctx), opts)
+
+	if cursor != "" {
+		values, err := decodeCursor(cursor, fields)
+		if err != nil {
+			return nil, errspec.ErrInvalidCursor.New(ctx).Wrap(err)
+		}
+		query = query.Where(cursorCondition(orders, values))
+	}
+
+	for _, order := range orders {
+		query = query.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: order.Column},
+			Desc:   order.Desc,
+		})
+	}
+
+	// 多查询一条用于判断是否还有下一页
+	var entities []T
+	if err := ignoreNotFound(query.Limit(limit + 1).Find(&entities).Error); err != nil {
+		return nil, err
+	}
+
+	result := &CursorResult[T]{List: entities}
+	if len(entities) > limit {
+		result.List = entities[:limit]
+		next, err := encodeCursor(ctx, fields, &result.List[limit-1])
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = next
+	}
+
+	return result, nil
+}
+
+// cursorFields 校验排序列并在需要时追加主键
+func cursorFields(ctx context.Context, sch *schema.Schema, orders []CursorOrder) ([]*schema.Field, []CursorOrder, error) {
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, nil, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	fields := make([]*schema.Field, 0, len(orders)+1)
+	hasPK := false
+	for _, order := range orders {
+		field, ok := sch.FieldsByDBName[order.Column]
+		if !ok {
+			return nil, nil, errspec.ErrInvalidColumn.New(ctx)
+		}
+		if field == pk {
+			hasPK = true
+		}
+		fields = append(fields, field)
+	}
+
+	if !hasPK {
+		desc := len(orders) > 0 && orders[len(orders)-1].Desc
+		orders = append(append([]CursorOrder{}, orders...), CursorOrder{Column: pk.DBName, Desc: desc})
+		fields = append(fields, pk)
+	}
+
+	return fields, orders, nil
+}
+
+// cursorCondition 生成键集分页的条件
+// 如 (a, b) 均为升序时生成 a > ? OR (a = ? AND b > ?)，支持各列方向不同
+func cursorCondition(orders []CursorOrder, values []any) clause.Expression {
+	branches := make([]clause.Expression, 0, len(orders))
+	for i, order := range orders {
+		exprs := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			exprs = append(exprs, clause.Eq{Column: cursorColumn(orders[j]), Value: values[j]})
+		}
+		if order.Desc {
+			exprs = append(exprs, clause.Lt{Column: cursorColumn(order), Value: values[i]})
+		} else {
+			exprs = append(exprs, clause.Gt{Column: cursorColumn(order), Value: values[i]})
+		}
+		branches = append(branches, clause.And(exprs...))
+	}
+	return clause.Or(branches...)
+}
+
+// cursorColumn 获取排序列
+func cursorColumn(order CursorOrder) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: order.Column}
+}
+
+// encodeCursor 将实体的排序列值编码为游标
+func encodeCursor(ctx context.Context, fields []*schema.Field, entity any) (string, error) {
+	rv := reflect.ValueOf(entity).Elem()
+
+	values := make([]any, 0, len(fields))
+	for _, field := range fields {
+		value, _ := field.ValueOf(ctx, rv)
+		values = append(values, value)
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor 解码游标，并按字段类型还原每列的值，如时间列还原为 time.Time
+func decodeCursor(cursor string, fields []*schema.Field) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, err
+	}
+	if len(raws) != len(fields) {
+		return nil, gorm.ErrInvalidValue
+	}
+
+	values := make([]any, len(fields))
+	for i, field := range fields {
+		ptr := reflect.New(field.FieldType)
+		if err := json.Unmarshal(raws[i], ptr.Interface()); err != nil {
+			return nil, err
+		}
+		values[i] = ptr.Elem().Interface()
+	}
+	return values, nil
+}
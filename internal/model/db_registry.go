@@ -0,0 +1,89 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm"
+)
+
+// DefaultDBName 默认数据库的名称
+const DefaultDBName = "default"
+
+// DBRegistry 命名数据库连接的注册表
+// 服务连接多个数据库（如主库和分析库）时，按名称集中管理连接，
+// 通过 RepoFor 创建绑定到指定数据库的仓库，不需要在各处传递 *gorm.DB。
+type DBRegistry struct {
+	mu  sync.RWMutex
+	dbs map[string]*gorm.DB
+}
+
+// NewDBRegistry 创建数据库注册表
+func NewDBRegistry() *DBRegistry {
+	return &DBRegistry{dbs: make(map[string]*gorm.DB)}
+}
+
+// Register 注册数据库连接，相同名称重复注册时覆盖之前的连接
+func (r *DBRegistry) Register(name string, db *gorm.DB) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dbs[name] = db
+}
+
+// Get 获取指定名称的数据库连接，未注册时返回 ErrDatabaseNotRegistered，错误消息按 ctx 的语言本地化
+func (r *DBRegistry) Get(ctx context.Context, name string) (*gorm.DB, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	db, ok := r.dbs[name]
+	if !ok {
+		return nil, errspec.ErrDatabaseNotRegistered.New(ctx)
+	}
+	return db, nil
+}
+
+// Names 获取所有已注册的数据库名称，按名称排序
+func (r *DBRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.dbs))
+	for name := range r.dbs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Close 关闭所有已注册的数据库连接，返回遇到的所有错误
+func (r *DBRegistry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for _, db := range r.dbs {
+		sqlDB, err := db.DB()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := sqlDB.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RepoFor 创建绑定到指定数据库的通用仓库
+// 如 model.RepoFor[Event](ctx, registry, "analytics")，数据库未注册时返回 ErrDatabaseNotRegistered
+func RepoFor[T Entity](ctx context.Context, registry *DBRegistry, name string) (*GenericRepo[T], error) {
+	db, err := registry.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return NewGenericRepo[T](db), nil
+}
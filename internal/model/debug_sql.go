@@ -0,0 +1,89 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm"
+)
+
+// debugSQLPluginName 调试 SQL 插件名称
+const debugSQLPluginName = "starter:debug_sql"
+
+// debugSQLKey 标记当前语句需要在错误中附带 SQL 的上下文键
+type debugSQLKey struct{}
+
+// EnableDebugSQL 开启调试模式，语句执行失败时在错误中附带渲染后的 SQL 和参数
+// 失败的错误被包装为 ErrDatabase，SQL 只出现在错误链中（记录到日志），返回给客户端的仍是通用的数据库错误消息。
+// 首次调用时在仓库使用的 *gorm.DB 上注册回调，应在初始化阶段调用，不要与查询并发执行。
+// 记录不存在（gorm.ErrRecordNotFound）不属于执行失败，不会被包装。
+func (r *GenericRepo[T]) EnableDebugSQL() error {
+	if _, ok := r.DB.Config.Plugins[debugSQLPluginName]; !ok {
+		if err := r.DB.Use(debugSQLPlugin{}); err != nil {
+			return err
+		}
+	}
+	r.debugSQL = true
+	return nil
+}
+
+// debugSQLPlugin 为带有调试标记的语句在执行失败时附带 SQL
+type debugSQLPlugin struct{}
+
+// Name 实现 gorm.Plugin 接口
+func (debugSQLPlugin) Name() string {
+	return debugSQLPluginName
+}
+
+// Initialize 实现 gorm.Plugin 接口
+// 为查询、增删改和原生 SQL 语句注册回调
+func (p debugSQLPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	processors := []interface {
+		Register(name string, fn func(*gorm.DB)) error
+	}{
+		callback.Query().After("gorm:query"),
+		callback.Create().After("gorm:create"),
+		callback.Update().After("gorm:update"),
+		callback.Delete().After("gorm:delete"),
+		callback.Row().After("gorm:row"),
+		callback.Raw().After("gorm:raw"),
+	}
+
+	for _, processor := range processors {
+		if err := processor.Register(debugSQLPluginName, p.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// after 语句执行失败时将错误包装为带有 SQL 的 ErrDatabase
+func (debugSQLPlugin) after(db *gorm.DB) {
+	ctx := db.Statement.Context
+	if db.Error == nil || ctx == nil || ctx.Value(debugSQLKey{}) == nil {
+		return
+	}
+	if errors.Is(db.Error, gorm.ErrRecordNotFound) || errspec.ErrDatabase.Is(db.Error) {
+		return
+	}
+
+	// 语句在生成 SQL 之前失败（如模型解析错误）时没有可附带的 SQL
+	sql := db.Statement.SQL.String()
+	if sql == "" {
+		return
+	}
+	rendered := db.Dialector.Explain(sql, db.Statement.Vars...)
+
+	db.Error = errspec.ErrDatabase.New(ctx).Wrap(fmt.Errorf("%w (sql: %s, args: %v)", db.Error, rendered, db.Statement.Vars))
+}
+
+// withDebugSQL 开启调试模式时为上下文添加调试标记
+func (r *GenericRepo[T]) withDebugSQL(ctx context.Context) context.Context {
+	if !r.debugSQL {
+		return ctx
+	}
+	return context.WithValue(ctx, debugSQLKey{}, true)
+}
@@ -65,8 +65,7 @@ type FileURLBuilder interface {
 
 // NewFileRepo 创建文件仓库
 func NewFileRepo(db *gorm.DB) *FileRepo {
-	genericRepo := NewGenericRepo[File](db)
-	genericRepo.ErrorCode = errspec.ErrFileNotFound.Code()
+	genericRepo := NewGenericRepoWithNotFound[File](db, errspec.ErrFileNotFound)
 
 	return &FileRepo{
 		GenericRepo: genericRepo,
@@ -76,8 +75,7 @@ func NewFileRepo(db *gorm.DB) *FileRepo {
 
 // NewFileRepoWithURLBuilder 创建带有自定义URL构建器的文件仓库
 func NewFileRepoWithURLBuilder(db *gorm.DB, urlBuilder FileURLBuilder) *FileRepo {
-	genericRepo := NewGenericRepo[File](db)
-	genericRepo.ErrorCode = errspec.ErrFileNotFound.Code()
+	genericRepo := NewGenericRepoWithNotFound[File](db, errspec.ErrFileNotFound)
 
 	return &FileRepo{
 		GenericRepo: genericRepo,
@@ -0,0 +1,394 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RepoMetrics 仓库操作的 Prometheus 指标
+// 同一个 Registerer 只能注册一次，多个仓库应共享同一个 RepoMetrics
+type RepoMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewRepoMetrics 创建并注册仓库操作指标
+// reg 为 nil 时使用 prometheus.DefaultRegisterer
+func NewRepoMetrics(reg prometheus.Registerer) (*RepoMetrics, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	m := &RepoMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "repository",
+			Name:      "operation_duration_seconds",
+			Help:      "Duration of repository operations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "table"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "repository",
+			Name:      "operation_errors_total",
+			Help:      "Total number of failed repository operations.",
+		}, []string{"operation", "table"}),
+	}
+
+	if err := reg.Register(m.duration); err != nil {
+		return nil, err
+	}
+	if err := reg.Register(m.errors); err != nil {
+		reg.Unregister(m.duration)
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// InstrumentedRepo 记录指标的仓库装饰器
+// 包装任意 Repository，按操作和表名记录耗时与错误数，方法签名保持不变。
+// 记录不存在（ErrRecordNotExist）属于正常的业务结果，不计入错误数。
+type InstrumentedRepo[T Entity] struct {
+	repo    Repository[T]
+	metrics *RepoMetrics
+	table   string
+}
+
+// NewInstrumentedRepo 创建记录指标的仓库
+func NewInstrumentedRepo[T Entity](repo Repository[T], metrics *RepoMetrics) *InstrumentedRepo[T] {
+	var entity T
+	return &InstrumentedRepo[T]{
+		repo:    repo,
+		metrics: metrics,
+		table:   entity.TableName(),
+	}
+}
+
+// observe 记录一次操作的耗时和结果
+func (r *InstrumentedRepo[T]) observe(operation string, start time.Time, err error) {
+	r.metrics.duration.WithLabelValues(operation, r.table).Observe(time.Since(start).Seconds())
+	if err != nil && !errspec.ErrRecordNotExist.Is(err) {
+		r.metrics.errors.WithLabelValues(operation, r.table).Inc()
+	}
+}
+
+// Create 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Create(ctx context.Context, entity *T) error {
+	start := time.Now()
+	err := r.repo.Create(ctx, entity)
+	r.observe("create", start, err)
+	return err
+}
+
+// BatchCreate 实现 Repository 接口
+func (r *InstrumentedRepo[T]) BatchCreate(ctx context.Context, entities []T, batchSize int) error {
+	start := time.Now()
+	err := r.repo.BatchCreate(ctx, entities, batchSize)
+	r.observe("batch_create", start, err)
+	return err
+}
+
+// BatchUpsert 实现 Repository 接口
+func (r *InstrumentedRepo[T]) BatchUpsert(ctx context.Context, entities []T, conflictColumns, updateColumns []string, batchSize int) error {
+	start := time.Now()
+	err := r.repo.BatchUpsert(ctx, entities, conflictColumns, updateColumns, batchSize)
+	r.observe("batch_upsert", start, err)
+	return err
+}
+
+// Get 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Get(ctx context.Context, id any, opts *QueryOptions) (*T, error) {
+	start := time.Now()
+	result, err := r.repo.Get(ctx, id, opts)
+	r.observe("get", start, err)
+	return result, err
+}
+
+// GetByIDs 实现 Repository 接口
+func (r *InstrumentedRepo[T]) GetByIDs(ctx context.Context, ids []any, opts *QueryOptions) (map[any]T, error) {
+	start := time.Now()
+	entities, err := r.repo.GetByIDs(ctx, ids, opts)
+	r.observe("get_by_ids", start, err)
+	return entities, err
+}
+
+// GetForUpdate 实现 Repository 接口
+func (r *InstrumentedRepo[T]) GetForUpdate(ctx context.Context, id any, opts *QueryOptions) (*T, error) {
+	start := time.Now()
+	result, err := r.repo.GetForUpdate(ctx, id, opts)
+	r.observe("get_for_update", start, err)
+	return result, err
+}
+
+// GetExactlyOne 实现 Repository 接口
+func (r *InstrumentedRepo[T]) GetExactlyOne(ctx context.Context, opts *QueryOptions) (*T, error) {
+	start := time.Now()
+	result, err := r.repo.GetExactlyOne(ctx, opts)
+	r.observe("get_exactly_one", start, err)
+	return result, err
+}
+
+// InsertMissing 实现 Repository 接口
+func (r *InstrumentedRepo[T]) InsertMissing(ctx context.Context, entities []T, keyColumns []string) (int64, error) {
+	start := time.Now()
+	result, err := r.repo.InsertMissing(ctx, entities, keyColumns)
+	r.observe("insert_missing", start, err)
+	return result, err
+}
+
+// Update 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Update(ctx context.Context, entity *T) error {
+	start := time.Now()
+	err := r.repo.Update(ctx, entity)
+	r.observe("update", start, err)
+	return err
+}
+
+// UpdateE 实现 Repository 接口
+func (r *InstrumentedRepo[T]) UpdateE(ctx context.Context, entity *T) (int64, error) {
+	start := time.Now()
+	result, err := r.repo.UpdateE(ctx, entity)
+	r.observe("update_e", start, err)
+	return result, err
+}
+
+// UpdateExisting 实现 Repository 接口
+func (r *InstrumentedRepo[T]) UpdateExisting(ctx context.Context, entity *T) error {
+	start := time.Now()
+	err := r.repo.UpdateExisting(ctx, entity)
+	r.observe("update_existing", start, err)
+	return err
+}
+
+// CreateAndReload 实现 Repository 接口
+func (r *InstrumentedRepo[T]) CreateAndReload(ctx context.Context, entity *T, opts *QueryOptions) error {
+	start := time.Now()
+	err := r.repo.CreateAndReload(ctx, entity, opts)
+	r.observe("create_and_reload", start, err)
+	return err
+}
+
+// UpdateAndReload 实现 Repository 接口
+func (r *InstrumentedRepo[T]) UpdateAndReload(ctx context.Context, entity *T, opts *QueryOptions) error {
+	start := time.Now()
+	err := r.repo.UpdateAndReload(ctx, entity, opts)
+	r.observe("update_and_reload", start, err)
+	return err
+}
+
+// UpdateFields 实现 Repository 接口
+func (r *InstrumentedRepo[T]) UpdateFields(ctx context.Context, id any, fields map[string]any) error {
+	start := time.Now()
+	err := r.repo.UpdateFields(ctx, id, fields)
+	r.observe("update_fields", start, err)
+	return err
+}
+
+// ToggleBool 实现 Repository 接口
+func (r *InstrumentedRepo[T]) ToggleBool(ctx context.Context, id any, column string) (bool, error) {
+	start := time.Now()
+	result, err := r.repo.ToggleBool(ctx, id, column)
+	r.observe("toggle_bool", start, err)
+	return result, err
+}
+
+// Increment 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Increment(ctx context.Context, id any, column string, delta int64) (int64, error) {
+	start := time.Now()
+	result, err := r.repo.Increment(ctx, id, column, delta)
+	r.observe("increment", start, err)
+	return result, err
+}
+
+// Delete 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Delete(ctx context.Context, id any) error {
+	start := time.Now()
+	err := r.repo.Delete(ctx, id)
+	r.observe("delete", start, err)
+	return err
+}
+
+// DeleteE 实现 Repository 接口
+func (r *InstrumentedRepo[T]) DeleteE(ctx context.Context, id any) (int64, error) {
+	start := time.Now()
+	result, err := r.repo.DeleteE(ctx, id)
+	r.observe("delete_e", start, err)
+	return result, err
+}
+
+// DeleteByIDs 实现 Repository 接口
+func (r *InstrumentedRepo[T]) DeleteByIDs(ctx context.Context, ids []any) (int64, error) {
+	start := time.Now()
+	n, err := r.repo.DeleteByIDs(ctx, ids)
+	r.observe("delete_by_ids", start, err)
+	return n, err
+}
+
+// DeleteBy 实现 Repository 接口
+func (r *InstrumentedRepo[T]) DeleteBy(ctx context.Context, id any, deletedBy any) error {
+	start := time.Now()
+	err := r.repo.DeleteBy(ctx, id, deletedBy)
+	r.observe("delete_by", start, err)
+	return err
+}
+
+// Restore 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Restore(ctx context.Context, id any) error {
+	start := time.Now()
+	err := r.repo.Restore(ctx, id)
+	r.observe("restore", start, err)
+	return err
+}
+
+// ListTrashed 实现 Repository 接口
+func (r *InstrumentedRepo[T]) ListTrashed(ctx context.Context, page, pageSize int, opts *QueryOptions) ([]T, error) {
+	start := time.Now()
+	result, err := r.repo.ListTrashed(ctx, page, pageSize, opts)
+	r.observe("list_trashed", start, err)
+	return result, err
+}
+
+// List 实现 Repository 接口
+func (r *InstrumentedRepo[T]) List(ctx context.Context, page, pageSize int, opts *QueryOptions) ([]T, error) {
+	start := time.Now()
+	result, err := r.repo.List(ctx, page, pageSize, opts)
+	r.observe("list", start, err)
+	return result, err
+}
+
+// ListWithCount 实现 Repository 接口
+func (r *InstrumentedRepo[T]) ListWithCount(ctx context.Context, page, pageSize int, opts *QueryOptions) (*Page[T], error) {
+	start := time.Now()
+	result, err := r.repo.ListWithCount(ctx, page, pageSize, opts)
+	r.observe("list_with_count", start, err)
+	return result, err
+}
+
+// ListWithRelationCount 实现 Repository 接口
+func (r *InstrumentedRepo[T]) ListWithRelationCount(ctx context.Context, relation string, page, pageSize int, opts *QueryOptions) ([]T, map[any]int64, error) {
+	start := time.Now()
+	entities, counts, err := r.repo.ListWithRelationCount(ctx, relation, page, pageSize, opts)
+	r.observe("list_with_relation_count", start, err)
+	return entities, counts, err
+}
+
+// ListByCursor 实现 Repository 接口
+func (r *InstrumentedRepo[T]) ListByCursor(ctx context.Context, cursor string, limit int, orders []CursorOrder, opts *QueryOptions) (*CursorResult[T], error) {
+	start := time.Now()
+	result, err := r.repo.ListByCursor(ctx, cursor, limit, orders, opts)
+	r.observe("list_by_cursor", start, err)
+	return result, err
+}
+
+// Each 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Each(ctx context.Context, batchSize int, opts *QueryOptions, fn func(T) error) error {
+	start := time.Now()
+	err := r.repo.Each(ctx, batchSize, opts, fn)
+	r.observe("each", start, err)
+	return err
+}
+
+// EachBatch 实现 Repository 接口
+func (r *InstrumentedRepo[T]) EachBatch(ctx context.Context, batchSize int, opts *QueryOptions, fn func([]T) error) error {
+	start := time.Now()
+	err := r.repo.EachBatch(ctx, batchSize, opts, fn)
+	r.observe("each_batch", start, err)
+	return err
+}
+
+// Count 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Count(ctx context.Context, opts *QueryOptions) (int64, error) {
+	start := time.Now()
+	result, err := r.repo.Count(ctx, opts)
+	r.observe("count", start, err)
+	return result, err
+}
+
+// EstimateCount 实现 Repository 接口
+func (r *InstrumentedRepo[T]) EstimateCount(ctx context.Context, opts *QueryOptions) (int64, error) {
+	start := time.Now()
+	result, err := r.repo.EstimateCount(ctx, opts)
+	r.observe("estimate_count", start, err)
+	return result, err
+}
+
+// Sum 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Sum(ctx context.Context, column string, opts *QueryOptions) (float64, error) {
+	start := time.Now()
+	result, err := r.repo.Sum(ctx, column, opts)
+	r.observe("sum", start, err)
+	return result, err
+}
+
+// SumDecimal 实现 Repository 接口
+func (r *InstrumentedRepo[T]) SumDecimal(ctx context.Context, column string, opts *QueryOptions) (decimal.Decimal, error) {
+	start := time.Now()
+	result, err := r.repo.SumDecimal(ctx, column, opts)
+	r.observe("sum_decimal", start, err)
+	return result, err
+}
+
+// CountByGroup 实现 Repository 接口
+func (r *InstrumentedRepo[T]) CountByGroup(ctx context.Context, column string, opts *QueryOptions) (map[string]int64, error) {
+	start := time.Now()
+	result, err := r.repo.CountByGroup(ctx, column, opts)
+	r.observe("count_by_group", start, err)
+	return result, err
+}
+
+// Find 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Find(ctx context.Context, filter *T, page, pageSize int, fields ...string) ([]T, int64, error) {
+	start := time.Now()
+	result, total, err := r.repo.Find(ctx, filter, page, pageSize, fields...)
+	r.observe("find", start, err)
+	return result, total, err
+}
+
+// FindOne 实现 Repository 接口
+func (r *InstrumentedRepo[T]) FindOne(ctx context.Context, filter *T, fields ...string) (*T, error) {
+	start := time.Now()
+	result, err := r.repo.FindOne(ctx, filter, fields...)
+	r.observe("find_one", start, err)
+	return result, err
+}
+
+// Transaction 实现 Repository 接口
+func (r *InstrumentedRepo[T]) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	start := time.Now()
+	err := r.repo.Transaction(ctx, fn)
+	r.observe("transaction", start, err)
+	return err
+}
+
+// TransactionWithRetry 实现 Repository 接口
+func (r *InstrumentedRepo[T]) TransactionWithRetry(ctx context.Context, maxRetries int, fn func(tx *gorm.DB) error) error {
+	start := time.Now()
+	err := r.repo.TransactionWithRetry(ctx, maxRetries, fn)
+	r.observe("transaction_with_retry", start, err)
+	return err
+}
+
+// TransactionWithOptions 实现 Repository 接口
+func (r *InstrumentedRepo[T]) TransactionWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(tx *gorm.DB) error) error {
+	start := time.Now()
+	err := r.repo.TransactionWithOptions(ctx, opts, fn)
+	r.observe("transaction_with_options", start, err)
+	return err
+}
+
+// WithTx 实现 Repository 接口，返回的仓库同样记录指标
+func (r *InstrumentedRepo[T]) WithTx(tx *gorm.DB) Repository[T] {
+	return &InstrumentedRepo[T]{
+		repo:    r.repo.WithTx(tx),
+		metrics: r.metrics,
+		table:   r.table,
+	}
+}
+
+// 确保 InstrumentedRepo 实现了 Repository 接口
+var _ Repository[User] = (*InstrumentedRepo[User])(nil)
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm/clause"
+)
+
+// JSONOp JSON 条件的比较方式
+type JSONOp string
+
+const (
+	// JSONOpEquals 路径上的值等于给定值
+	JSONOpEquals JSONOp = "eq"
+	// JSONOpContains 路径上的值包含给定值：数组包含该元素，对象包含该子对象
+	JSONOpContains JSONOp = "contains"
+)
+
+// jsonPathSegment 合法的路径片段：标识符或数组下标
+var jsonPathSegment = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*|[0-9]+)$`)
+
+// JSONCondition JSON 列的查询条件，按数据库方言生成 JSON_EXTRACT / #> 等语句
+type JSONCondition struct {
+	Column string // JSON 列名，必须是实体的列
+	Path   string // 键路径，以点分隔，数组下标使用数字，如 "address.city"、"tags.0"；为空表示整列
+	Op     JSONOp // 比较方式
+	Value  any    // 比较的值，按 JSON 编码后与列中的值比较
+}
+
+// JSONEquals 创建 JSON 路径上的值等于 value 的条件
+// 如 JSONEquals("profile", "address.city", "Shanghai")
+func JSONEquals(column, path string, value any) JSONCondition {
+	return JSONCondition{Column: column, Path: path, Op: JSONOpEquals, Value: value}
+}
+
+// JSONContains 创建 JSON 路径上的值包含 value 的条件
+// 如 JSONContains("profile", "tags", "vip") 匹配 tags 数组中含有 "vip" 的记录
+func JSONContains(column, path string, value any) JSONCondition {
+	return JSONCondition{Column: column, Path: path, Op: JSONOpContains, Value: value}
+}
+
+// jsonCondition 生成 JSON 条件的表达式
+// 列必须是实体的列，路径片段只能是标识符或数组下标，列和路径都不会拼接到 SQL 中。
+// 支持 MySQL、Postgres 和 SQLite，SQLite 的包含条件只支持数组元素为标量的情况。
+func (r *GenericRepo[T]) jsonCondition(ctx context.Context, dialect string, cond JSONCondition) (clause.Expression, error) {
+	sch, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sch.FieldsByDBName[cond.Column]; !ok {
+		return nil, errspec.ErrInvalidColumn.New(ctx)
+	}
+
+	var segments []string
+	if cond.Path != "" {
+		segments = strings.Split(cond.Path, ".")
+		for _, segment := range segments {
+			if !jsonPathSegment.MatchString(segment) {
+				return nil, errspec.ErrInvalidJSONPath.New(ctx)
+			}
+		}
+	}
+	if cond.Op != JSONOpEquals && cond.Op != JSONOpContains {
+		return nil, errspec.ErrInvalidJSONOperator.New(ctx)
+	}
+
+	value, err := json.Marshal(cond.Value)
+	if err != nil {
+		return nil, errspec.ErrInvalidJSONValue.New(ctx).Wrap(err)
+	}
+
+	column := clause.Column{Table: clause.CurrentTable, Name: cond.Column}
+	switch dialect {
+	case "mysql":
+		path := jsonDollarPath(segments)
+		if cond.Op == JSONOpContains {
+			return clause.Expr{SQL: "JSON_CONTAINS(?, ?, ?)", Vars: []any{column, string(value), path}}, nil
+		}
+		return clause.Expr{SQL: "JSON_EXTRACT(?, ?) = CAST(? AS JSON)", Vars: []any{column, path, string(value)}}, nil
+	case "postgres":
+		path := "{" + strings.Join(segments, ",") + "}"
+		if cond.Op == JSONOpContains {
+			return clause.Expr{SQL: "(CAST(? AS jsonb) #> CAST(? AS text[])) @> CAST(? AS jsonb)", Vars: []any{column, path, string(value)}}, nil
+		}
+		return clause.Expr{SQL: "(CAST(? AS jsonb) #> CAST(? AS text[])) = CAST(? AS jsonb)", Vars: []any{column, path, string(value)}}, nil
+	case "sqlite":
+		path := jsonDollarPath(segments)
+		if cond.Op == JSONOpContains {
+			return clause.Expr{SQL: "EXISTS (SELECT 1 FROM json_each(?, ?) WHERE json_each.value = json_extract(?, '$'))", Vars: []any{column, path, string(value)}}, nil
+		}
+		return clause.Expr{SQL: "json_extract(?, ?) = json_extract(?, '$')", Vars: []any{column, path, string(value)}}, nil
+	default:
+		return nil, errspec.ErrJSONQueryNotSupported.New(ctx)
+	}
+}
+
+// jsonDollarPath 将路径片段转换为 MySQL / SQLite 的 $.a.b[0] 形式
+func jsonDollarPath(segments []string) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, segment := range segments {
+		if segment[0] >= '0' && segment[0] <= '9' {
+			b.WriteString("[" + segment + "]")
+		} else {
+			b.WriteString("." + segment)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package model
+
+import "context"
+
+// DefaultMaxPageSize 默认的每页最大条数，仓库的 MaxPageSize 为 0 时使用，设为 0 表示不限制
+var DefaultMaxPageSize = 100
+
+// Page 分页查询结果，Page 和 PageSize 为实际生效的分页参数
+type Page[T any] struct {
+	List     []T   // 当前页的数据
+	Total    int64 // 符合条件的总数
+	Page     int   // 实际使用的页码
+	PageSize int   // 实际使用的每页条数
+}
+
+// normalizePage 将分页参数限制在有效范围内
+// page 至少为 1，pageSize 限制在 [1, MaxPageSize]，防止客户端一次请求过多数据
+func (r *GenericRepo[T]) normalizePage(page, pageSize int) (int, int) {
+	maxPageSize := r.MaxPageSize
+	if maxPageSize <= 0 {
+		maxPageSize = DefaultMaxPageSize
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 1
+	}
+	if maxPageSize > 0 && pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+// ListWithCount 分页获取实体列表和符合条件的总数
+// 返回的 Page 和 PageSize 为限制后实际生效的值，可直接用于构造分页响应
+func (r *GenericRepo[T]) ListWithCount(ctx context.Context, page, pageSize int, opts *QueryOptions) (*Page[T], error) {
+	page, pageSize = r.normalizePage(page, pageSize)
+
+	total, err := r.Count(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Page[T]{List: []T{}, Total: total, Page: page, PageSize: pageSize}
+	if total == 0 {
+		return result, nil
+	}
+
+	list, err := r.List(ctx, page, pageSize, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.List = list
+
+	return result, nil
+}
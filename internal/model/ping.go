@@ -0,0 +1,32 @@
+package model
+
+import (
+	"context"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm"
+)
+
+// PingDB 检查数据库连接是否可用
+// 遵循 ctx 的截止时间，数据库无响应时不会一直阻塞，失败时返回 ErrDatabaseUnavailable
+func PingDB(ctx context.Context, db *gorm.DB) error {
+	if db == nil {
+		return errspec.ErrDatabaseUnavailable.New(ctx)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errspec.ErrDatabaseUnavailable.New(ctx).Wrap(err)
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return errspec.ErrDatabaseUnavailable.New(ctx).Wrap(err)
+	}
+
+	return nil
+}
+
+// Ping 检查仓库使用的数据库连接是否可用
+func (r *GenericRepo[T]) Ping(ctx context.Context) error {
+	return PingDB(ctx, r.DB)
+}
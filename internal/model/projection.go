@@ -0,0 +1,62 @@
+package model
+
+import (
+	"context"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm/clause"
+)
+
+// ListInto 分页查询 T 对应的表，并将结果扫描到自定义的视图结构体 R
+// 适用于连接查询后投影为 DTO 的列表接口，查询选项与分页的处理和 List 相同。
+// R 的字段按列名与查询结果对应，可通过 options.WithSelect 指定投影的列。
+// page 和 pageSize 必须大于 0，否则返回 ErrInvalidParams，pageSize 超过上限时按上限查询。
+func ListInto[T Entity, R any](ctx context.Context, repo *GenericRepo[T], dest *[]R, page, pageSize int, opts *QueryOptions) error {
+	if dest == nil {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+	if page < 1 || pageSize < 1 {
+		return errspec.ErrInvalidParams.New(ctx, struct{ Params string }{"page and pageSize must be positive"})
+	}
+
+	page, pageSize = repo.normalizePage(page, pageSize)
+
+	var entity T
+	query := repo.withContext(ctx).Model(&entity).
+		Offset((page - 1) * pageSize).
+		Limit(pageSize)
+
+	query = repo.applyQueryOptions(query, opts)
+
+	return ignoreNotFound(query.Find(dest).Error)
+}
+
+// DistinctValues 查询 T 对应的表中某一列的去重值，按该列升序排列，用于填充筛选下拉框等场景
+// column 可以是列名或字段名，不存在时返回 ErrInvalidColumn；NULL 值会被排除，V 无需能接收 NULL。
+// 查询选项与 List 相同，但 opts 中不应再指定其他排序列，部分数据库要求 DISTINCT 的排序列出现在查询列中。
+func DistinctValues[T Entity, V any](ctx context.Context, repo *GenericRepo[T], column string, opts *QueryOptions) ([]V, error) {
+	sch, err := repo.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	field := sch.LookUpField(column)
+	if field == nil || field.DBName == "" {
+		return nil, errspec.ErrInvalidColumn.New(ctx)
+	}
+
+	var entity T
+	query := repo.applyQueryOptions(repo.withContext(ctx).Model(&entity), opts)
+
+	col := clause.Column{Table: clause.CurrentTable, Name: field.DBName}
+	values := make([]V, 0)
+	err = query.
+		Where(clause.Neq{Column: col, Value: nil}).
+		Order(clause.OrderByColumn{Column: col}).
+		Distinct().
+		Pluck(field.DBName, &values).Error
+	if err := ignoreNotFound(err); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
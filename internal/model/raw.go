@@ -0,0 +1,30 @@
+package model
+
+import (
+	"context"
+	"strings"
+
+	"github.com/limitcool/starter/internal/errspec"
+)
+
+// Raw 执行原生 SQL 查询并将结果映射为实体列表
+// 用于仓库方法无法表达的复杂查询，如手工优化的报表查询。
+// SQL 按原样执行，不会应用软删除等作用域，SQL 的正确性与安全性由调用方负责，
+// 参数必须通过 args 占位符传入，不要拼接到 sql 中。
+func (r *GenericRepo[T]) Raw(ctx context.Context, sql string, args ...any) ([]T, error) {
+	var entities []T
+	if err := r.RawScan(ctx, &entities, sql, args...); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// RawScan 执行原生 SQL 查询并将结果扫描到 dest
+// dest 可以是任意结构体、结构体切片或基础类型的指针，注意事项与 Raw 相同
+func (r *GenericRepo[T]) RawScan(ctx context.Context, dest any, sql string, args ...any) error {
+	if strings.TrimSpace(sql) == "" || dest == nil {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	return r.withContext(ctx).Raw(sql, args...).Scan(dest).Error
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ListWithRelationCount 分页获取实体列表，并统计每个实体的关联记录数
+// relation 为 has many 或 many to many 关联的字段名，如 "Orders"。
+// 关联记录不会被加载，所有实体的数量通过一条 GROUP BY 语句统计，避免逐行计数的 N+1 查询。
+// 返回的 map 以实体主键为键，没有关联记录的实体数量为 0；has many 关联会排除已软删除的记录。
+// 关联不存在、不是一对多或多对多关联、或使用复合外键时返回 ErrInvalidRelation。
+func (r *GenericRepo[T]) ListWithRelationCount(ctx context.Context, relation string, page, pageSize int, opts *QueryOptions) ([]T, map[any]int64, error) {
+	sch, err := r.parseSchema()
+	if err != nil {
+		return nil, nil, err
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, nil, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	rel, ok := sch.Relationships.Relations[relation]
+	if !ok || (rel.Type != schema.HasMany && rel.Type != schema.Many2Many) {
+		return nil, nil, errspec.ErrInvalidRelation.New(ctx)
+	}
+	ref, ok := relationOwnReference(rel)
+	if !ok {
+		return nil, nil, errspec.ErrInvalidRelation.New(ctx)
+	}
+
+	entities, err := r.List(ctx, page, pageSize, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	counts := make(map[any]int64, len(entities))
+	if len(entities) == 0 {
+		return entities, counts, nil
+	}
+
+	// 关联键可能不是主键，按关联键的值找到对应的实体主键
+	owners := make(map[string][]any, len(entities))
+	keys := make([]any, 0, len(entities))
+	for i := range entities {
+		rv := reflect.ValueOf(&entities[i]).Elem()
+		id, _ := pk.ValueOf(ctx, rv)
+		counts[id] = 0
+
+		key, zero := ref.PrimaryKey.ValueOf(ctx, rv)
+		if zero {
+			continue
+		}
+		k := relationKey(key)
+		if _, ok := owners[k]; !ok {
+			keys = append(keys, key)
+		}
+		owners[k] = append(owners[k], id)
+	}
+	if len(keys) == 0 {
+		return entities, counts, nil
+	}
+
+	query := r.relationCountQuery(ctx, rel)
+	column := clause.Column{Table: clause.CurrentTable, Name: ref.ForeignKey.DBName}
+	rows, err := query.
+		Select("?, COUNT(*)", column).
+		Where(clause.IN{Column: column, Values: keys}).
+		Clauses(clause.GroupBy{Columns: []clause.Column{column}}).
+		Rows()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			key   any
+			count int64
+		)
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, nil, err
+		}
+		for _, id := range owners[relationKey(key)] {
+			counts[id] = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return entities, counts, nil
+}
+
+// relationOwnReference 获取关联中引用当前实体的外键，只支持单列外键
+func relationOwnReference(rel *schema.Relationship) (*schema.Reference, bool) {
+	var own *schema.Reference
+	for _, ref := range rel.References {
+		if !ref.OwnPrimaryKey || ref.PrimaryKey == nil {
+			continue
+		}
+		if own != nil {
+			return nil, false
+		}
+		own = ref
+	}
+	return own, own != nil
+}
+
+// relationCountQuery 创建统计关联记录数的查询
+// 多对多关联统计中间表，一对多关联统计关联表并带上多态类型条件
+func (r *GenericRepo[T]) relationCountQuery(ctx context.Context, rel *schema.Relationship) *gorm.DB {
+	if rel.Type == schema.Many2Many {
+		return r.withContext(ctx).Table(rel.JoinTable.Table)
+	}
+
+	query := r.withContext(ctx).Model(reflect.New(rel.FieldSchema.ModelType).Interface())
+	for _, ref := range rel.References {
+		if ref.PrimaryKey == nil && ref.PrimaryValue != "" {
+			query = query.Where(clause.Eq{
+				Column: clause.Column{Table: clause.CurrentTable, Name: ref.ForeignKey.DBName},
+				Value:  ref.PrimaryValue,
+			})
+		}
+	}
+	return query
+}
+
+// relationKey 将关联键的值转换为字符串，使实体字段的值与数据库扫描出的值可以比较
+func relationKey(v any) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
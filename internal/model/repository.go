@@ -2,10 +2,22 @@ package model
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
+	i18nerrx "github.com/epkgs/i18n/errorx"
 	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/pkg/ctxutil"
+	"github.com/limitcool/starter/internal/pkg/errorx"
 	"github.com/limitcool/starter/internal/pkg/options"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
 )
 
 // Entity 实体接口
@@ -14,16 +26,62 @@ type Entity interface {
 	TableName() string
 }
 
+// PreloadSpec 带条件和排序的预加载
+type PreloadSpec struct {
+	// 关联名称，如 "Comments" 或 "Orders.Items"
+	Name string
+	// 关联记录的过滤条件
+	Condition string
+	// 过滤参数
+	Args []any
+	// 关联记录的排序，如 "created_at DESC"，不应直接使用客户端传入的值
+	OrderBy string
+}
+
+// JoinClause 连接子句
+type JoinClause struct {
+	// 连接语句，如 "JOIN roles ON roles.id = users.role_id AND roles.status = ?"
+	Query string
+	// 连接参数
+	Args []any
+}
+
 // QueryOptions 查询选项
 type QueryOptions struct {
 	// 查询条件
 	Condition string
 	// 查询参数
 	Args []any
+	// JSON 列条件，按数据库方言生成，与 Condition 同时生效
+	JSONConditions []JSONCondition
 	// 查询选项
 	Opts []options.Option
-	// 预加载关联
+	// 预加载关联，通过额外的查询加载关联数据，不能用于过滤
 	Preloads []string
+	// 带条件和排序的预加载
+	PreloadSpecs []PreloadSpec
+	// 连接子句，用于按关联表的列过滤或排序，不会加载关联数据
+	Joins []JoinClause
+	// 分组列
+	GroupBy []string
+	// 分组过滤条件，如 "COUNT(*) > ?"
+	Having string
+	// 分组过滤参数
+	HavingArgs []any
+	// 去除重复行，连接子表查询时避免返回重复的主表记录
+	Distinct bool
+	// 按指定列去重（DISTINCT ON），仅支持 Postgres，
+	// 且 ORDER BY 需以这些列开头
+	DistinctOn []string
+	// 包含已软删除的记录
+	Unscoped bool
+	// 只查询已软删除的记录，隐含 Unscoped，实体不支持软删除时返回 ErrSoftDeleteNotSupported
+	OnlyDeleted bool
+	// 索引提示，生成 USE INDEX (...)，用于优化器选错索引时指定索引。
+	// 仅 MySQL 支持，其他数据库忽略该选项，不影响查询结果
+	IndexHints []string
+	// 使用 FORCE INDEX 代替 USE INDEX，仅在设置了 IndexHints 时生效
+	ForceIndex bool
 }
 
 // Repository 数据库操作接口
@@ -32,50 +90,219 @@ type Repository[T Entity] interface {
 	// Create 创建实体
 	Create(ctx context.Context, entity *T) error
 
+	// BatchCreate 分批创建实体，ctx 取消后停止写入后续批次
+	BatchCreate(ctx context.Context, entities []T, batchSize int) error
+
+	// BatchUpsert 在一个事务中分批插入或更新实体，冲突时更新指定的列
+	BatchUpsert(ctx context.Context, entities []T, conflictColumns, updateColumns []string, batchSize int) error
+
 	// Get 根据ID或条件获取单个实体
 	// id: 实体ID，如果为nil，则使用condition和args
 	// opts: 查询选项，可以为nil
 	Get(ctx context.Context, id any, opts *QueryOptions) (*T, error)
 
-	// Update 更新实体
+	// GetByIDs 按ID批量获取实体，返回以主键值为键的映射，不存在的ID被忽略
+	GetByIDs(ctx context.Context, ids []any, opts *QueryOptions) (map[any]T, error)
+
+	// GetForUpdate 根据ID或条件获取单个实体并加行锁（SELECT ... FOR UPDATE）
+	// 必须在事务中调用，否则返回 ErrNotInTransaction
+	GetForUpdate(ctx context.Context, id any, opts *QueryOptions) (*T, error)
+
+	// GetExactlyOne 根据条件获取唯一的实体
+	// 没有匹配记录时返回 ErrRecordNotExist，匹配到多条记录时返回 ErrMultipleRows
+	GetExactlyOne(ctx context.Context, opts *QueryOptions) (*T, error)
+
+	// InsertMissing 批量插入键列尚不存在的实体，返回实际插入的数量
+	InsertMissing(ctx context.Context, entities []T, keyColumns []string) (int64, error)
+
+	// Update 保存整个实体，零值字段也会写入数据库
 	Update(ctx context.Context, entity *T) error
 
+	// UpdateE 保存整个实体，返回影响的行数
+	UpdateE(ctx context.Context, entity *T) (int64, error)
+
+	// UpdateExisting 按主键更新已存在的实体，记录不存在时返回记录不存在错误
+	UpdateExisting(ctx context.Context, entity *T) error
+
+	// CreateAndReload 创建实体后按主键重新查询，使实体包含数据库生成的列
+	CreateAndReload(ctx context.Context, entity *T, opts *QueryOptions) error
+
+	// UpdateAndReload 保存实体后按主键重新查询，使实体包含数据库生成的列
+	UpdateAndReload(ctx context.Context, entity *T, opts *QueryOptions) error
+
+	// UpdateFields 只更新指定的列
+	UpdateFields(ctx context.Context, id any, fields map[string]any) error
+
+	// ToggleBool 翻转布尔列的值并返回翻转后的值
+	ToggleBool(ctx context.Context, id any, column string) (bool, error)
+
+	// Increment 原子地为整数列增加 delta 并返回增加后的值
+	Increment(ctx context.Context, id any, column string, delta int64) (int64, error)
+
 	// Delete 删除实体
 	Delete(ctx context.Context, id any) error
 
+	// DeleteE 删除实体，返回影响的行数
+	DeleteE(ctx context.Context, id any) (int64, error)
+
+	// DeleteByIDs 按ID批量删除实体，返回实际删除的数量
+	DeleteByIDs(ctx context.Context, ids []any) (int64, error)
+
+	// DeleteBy 软删除实体并记录删除人，实体没有 deleted_by 列时只记录删除时间
+	DeleteBy(ctx context.Context, id any, deletedBy any) error
+
+	// Restore 恢复已软删除的实体
+	Restore(ctx context.Context, id any) error
+
+	// ListTrashed 获取已软删除的实体列表
+	ListTrashed(ctx context.Context, page, pageSize int, opts *QueryOptions) ([]T, error)
+
 	// List 获取实体列表
 	// page, pageSize: 分页参数
 	// opts: 查询选项，可以为nil
 	List(ctx context.Context, page, pageSize int, opts *QueryOptions) ([]T, error)
 
+	// ListWithCount 分页获取实体列表和总数，返回实际生效的分页参数
+	ListWithCount(ctx context.Context, page, pageSize int, opts *QueryOptions) (*Page[T], error)
+
+	// ListWithRelationCount 分页获取实体列表，并统计每个实体的关联记录数
+	ListWithRelationCount(ctx context.Context, relation string, page, pageSize int, opts *QueryOptions) ([]T, map[any]int64, error)
+
+	// ListByCursor 按游标分页获取实体列表，支持多列排序，排序值相同时按主键区分
+	ListByCursor(ctx context.Context, cursor string, limit int, orders []CursorOrder, opts *QueryOptions) (*CursorResult[T], error)
+
+	// Each 按主键升序分批读取实体并逐个调用 fn，fn 返回错误时停止遍历
+	Each(ctx context.Context, batchSize int, opts *QueryOptions, fn func(T) error) error
+
+	// EachBatch 按主键升序分批读取实体并逐批调用 fn，fn 返回错误时停止遍历
+	EachBatch(ctx context.Context, batchSize int, opts *QueryOptions, fn func([]T) error) error
+
 	// Count 获取实体总数
 	// opts: 查询选项，可以为nil
 	Count(ctx context.Context, opts *QueryOptions) (int64, error)
 
+	// EstimateCount 获取实体总数的估计值，Postgres 上没有过滤条件时读取统计信息，否则精确统计
+	EstimateCount(ctx context.Context, opts *QueryOptions) (int64, error)
+
+	// Sum 对列求和，结果为 float64，不适用于金额等要求精确的列
+	Sum(ctx context.Context, column string, opts *QueryOptions) (float64, error)
+
+	// SumDecimal 对列精确求和，适用于金额等 DECIMAL 列
+	SumDecimal(ctx context.Context, column string, opts *QueryOptions) (decimal.Decimal, error)
+
+	// CountByGroup 按列分组统计数量，返回以分组值（字符串形式）为键的映射
+	CountByGroup(ctx context.Context, column string, opts *QueryOptions) (map[string]int64, error)
+
+	// Find 以结构体的非零值字段作为等值条件分页查询，同时返回符合条件的总数
+	// fields: 强制作为条件的字段，即使字段为零值
+	Find(ctx context.Context, filter *T, page, pageSize int, fields ...string) ([]T, int64, error)
+
+	// FindOne 以结构体的非零值字段作为等值条件获取单个实体
+	FindOne(ctx context.Context, filter *T, fields ...string) (*T, error)
+
 	// Transaction 在事务中执行函数
 	Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error
 
+	// TransactionWithRetry 在事务中执行函数，遇到死锁或序列化失败时最多重试 maxRetries 次
+	TransactionWithRetry(ctx context.Context, maxRetries int, fn func(tx *gorm.DB) error) error
+
+	// TransactionWithOptions 使用指定的隔离级别和只读选项执行事务，串行化隔离级别遇到序列化失败时自动重试
+	TransactionWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(tx *gorm.DB) error) error
+
 	// WithTx 使用事务
 	WithTx(tx *gorm.DB) Repository[T]
 }
 
+// 预加载限制的默认值
+const (
+	DefaultMaxPreloads     = 10 // 单次查询最多预加载的关联数量
+	DefaultMaxPreloadDepth = 3  // 预加载关联的最大嵌套深度，如 "Orders.Items" 深度为2
+)
+
 // GenericRepo 通用仓库实现
 type GenericRepo[T Entity] struct {
-	DB        *gorm.DB
-	ErrorCode int // 用于NotFound错误
+	DB              *gorm.DB
+	NotFoundErr     *i18nerrx.DefinitionSimple[*errorx.AppError] // 记录不存在时返回的错误，默认为 ErrRecordNotExist
+	MaxPreloads     int                                          // 最多预加载的关联数量，0表示不限制
+	MaxPreloadDepth int                                          // 预加载关联的最大嵌套深度，0表示不限制
+	MaxPageSize     int                                          // 每页最大条数，0表示使用 DefaultMaxPageSize
+
+	txCtx    context.Context // 开启事务时的上下文，由 WithTx 设置
+	debugSQL bool            // 执行失败时在错误中附带 SQL，由 EnableDebugSQL 开启
 }
 
 // NewGenericRepo 创建通用仓库
 func NewGenericRepo[T Entity](db *gorm.DB) *GenericRepo[T] {
 	return &GenericRepo[T]{
-		DB:        db,
-		ErrorCode: errspec.ErrNotFound.Code(), // 默认错误码
+		DB:              db,
+		NotFoundErr:     errspec.ErrRecordNotExist,
+		MaxPreloads:     DefaultMaxPreloads,
+		MaxPreloadDepth: DefaultMaxPreloadDepth,
+	}
+}
+
+// NewGenericRepoWithNotFound 创建通用仓库，记录不存在时返回指定的错误
+// 如用户仓库传入 errspec.ErrUserNotFound，客户端收到用户不存在的错误码和消息，能区分不同实体的不存在错误
+func NewGenericRepoWithNotFound[T Entity](db *gorm.DB, def *i18nerrx.DefinitionSimple[*errorx.AppError]) *GenericRepo[T] {
+	repo := NewGenericRepo[T](db)
+	repo.NotFoundErr = def
+	return repo
+}
+
+// notFoundError 创建记录不存在的错误
+// 错误码和消息取自 NotFoundErr，错误链中只有这一个带错误码的错误，包装后也不会被其他错误码覆盖；
+// 原因为 gorm.ErrRecordNotFound，errspec.ErrRecordNotExist.Is 通过原因匹配，对所有仓库依然成立
+func (r *GenericRepo[T]) notFoundError(ctx context.Context, cause error) error {
+	def := r.NotFoundErr
+	if def == nil {
+		def = errspec.ErrRecordNotExist
+	}
+	return def.New(ctx).Wrap(notFoundCause{cause})
+}
+
+// notFoundCause 记录不存在错误的原因
+// 使用自定义错误定义的仓库也能通过 errspec.ErrRecordNotExist.Is 判断记录不存在，
+// 且原因本身不带错误码，不影响响应中的错误码
+type notFoundCause struct {
+	error
+}
+
+// Unwrap 返回原始错误，通常为 gorm.ErrRecordNotFound
+func (e notFoundCause) Unwrap() error {
+	return e.error
+}
+
+// Is 与 ErrRecordNotExist 的错误码相同的错误匹配
+func (e notFoundCause) Is(target error) bool {
+	t, ok := target.(interface{ Code() int })
+	return ok && t.Code() == errspec.ErrRecordNotExist.Code()
+}
+
+// ignoreNotFound 忽略列表类查询返回的 gorm.ErrRecordNotFound
+// Find、Count 等查询没有结果时不是错误，但部分 GORM 配置或插件在结果为空时仍会返回该错误，
+// 列表类方法统一视为空结果，只有 Get 等获取单个实体的方法将其转换为 ErrRecordNotExist。
+func ignoreNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
 	}
+	return err
+}
+
+// withContext 创建带上下文的查询
+// 上下文要求读主库时，强制本次查询使用主库
+func (r *GenericRepo[T]) withContext(ctx context.Context) *gorm.DB {
+	// 事务内的调用可能传入不含请求信息的上下文，从开启事务时的上下文中补充日志关联字段
+	ctx = ctxutil.WithFallback(ctx, r.txCtx)
+	db := r.DB.WithContext(r.withDebugSQL(ctx))
+	if ctxutil.IsReadFromPrimary(ctx) {
+		db = db.Clauses(dbresolver.Write)
+	}
+	return db
 }
 
 // Create 创建实体
 func (r *GenericRepo[T]) Create(ctx context.Context, entity *T) error {
-	return r.DB.WithContext(ctx).Create(entity).Error
+	return r.withContext(ctx).Create(entity).Error
 }
 
 // applyQueryOptions 应用查询选项
@@ -84,12 +311,35 @@ func (r *GenericRepo[T]) applyQueryOptions(query *gorm.DB, opts *QueryOptions) *
 		return query
 	}
 
+	// 限制预加载的数量和深度，防止客户端驱动的预加载拖垮数据库
+	preloads := opts.Preloads
+	if len(opts.PreloadSpecs) > 0 {
+		preloads = append(preloads[:len(preloads):len(preloads)], preloadNames(opts.PreloadSpecs)...)
+	}
+	if err := r.checkPreloads(query.Statement.Context, preloads); err != nil {
+		_ = query.AddError(err)
+		return query
+	}
+
 	// 应用预加载
 	if opts.Preloads != nil {
 		for _, preload := range opts.Preloads {
 			query = query.Preload(preload)
 		}
 	}
+	for _, spec := range opts.PreloadSpecs {
+		query = query.Preload(spec.Name, spec.apply)
+	}
+
+	// 应用索引提示
+	if len(opts.IndexHints) > 0 && query.Dialector.Name() == "mysql" {
+		query = r.applyIndexHints(query, opts.IndexHints, opts.ForceIndex)
+	}
+
+	// 应用连接
+	for _, join := range opts.Joins {
+		query = query.Joins(join.Query, join.Args...)
+	}
 
 	// 应用查询选项
 	if len(opts.Opts) > 0 {
@@ -100,16 +350,129 @@ func (r *GenericRepo[T]) applyQueryOptions(query *gorm.DB, opts *QueryOptions) *
 	if opts.Condition != "" {
 		query = query.Where(opts.Condition, opts.Args...)
 	}
+	for _, cond := range opts.JSONConditions {
+		expr, err := r.jsonCondition(query.Statement.Context, query.Dialector.Name(), cond)
+		if err != nil {
+			_ = query.AddError(err)
+			return query
+		}
+		query = query.Where(expr)
+	}
+
+	// 应用软删除范围，Count 使用相同的选项，回收站的总数与列表一致
+	if opts.Unscoped || opts.OnlyDeleted {
+		query = query.Unscoped()
+	}
+	if opts.OnlyDeleted {
+		_, column, err := r.softDeleteColumn(query.Statement.Context)
+		if err != nil {
+			_ = query.AddError(err)
+			return query
+		}
+		query = query.Where(clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: nil})
+	}
+
+	// 应用去重
+	if len(opts.DistinctOn) > 0 {
+		if query.Dialector.Name() != "postgres" {
+			_ = query.AddError(errspec.ErrDistinctOnNotSupported.New(query.Statement.Context))
+			return query
+		}
+		query = query.Select("DISTINCT ON (" + strings.Join(opts.DistinctOn, ", ") + ") " + r.tableName(query) + ".*")
+	} else if opts.Distinct {
+		if len(query.Statement.Selects) == 0 {
+			// 只对主表列去重，避免连接表的列参与比较
+			query = query.Distinct(r.tableName(query) + ".*")
+		} else {
+			query = query.Distinct()
+		}
+	}
+
+	// 应用分组
+	for _, column := range opts.GroupBy {
+		query = query.Group(column)
+	}
+	if opts.Having != "" {
+		query = query.Having(opts.Having, opts.HavingArgs...)
+	}
 
 	return query
 }
 
+// applyIndexHints 在表名后添加索引提示，索引名按标识符转义
+func (r *GenericRepo[T]) applyIndexHints(query *gorm.DB, indexes []string, force bool) *gorm.DB {
+	table := r.tableName(query)
+
+	hint := "USE"
+	if force {
+		hint = "FORCE"
+	}
+
+	vars := make([]any, 0, len(indexes)+1)
+	vars = append(vars, clause.Table{Name: table})
+	for _, index := range indexes {
+		vars = append(vars, clause.Column{Name: index})
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(indexes)), ", ")
+	query = query.Table("? "+hint+" INDEX ("+placeholders+")", vars...)
+	// Table 使用表达式时无法解析出表名，手动设置以便列名限定和软删除条件使用
+	query.Statement.Table = table
+	return query
+}
+
+// tableName 获取查询的表名，仓库绑定到其他表（如临时表）时使用绑定的表名
+func (r *GenericRepo[T]) tableName(query *gorm.DB) string {
+	if query.Statement.Table != "" {
+		return query.Statement.Table
+	}
+	var entity T
+	return entity.TableName()
+}
+
+// apply 在预加载的查询上应用条件和排序
+func (p PreloadSpec) apply(db *gorm.DB) *gorm.DB {
+	if p.Condition != "" {
+		db = db.Where(p.Condition, p.Args...)
+	}
+	if p.OrderBy != "" {
+		db = db.Order(p.OrderBy)
+	}
+	return db
+}
+
+// preloadNames 获取预加载的关联名称
+func preloadNames(specs []PreloadSpec) []string {
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		names = append(names, spec.Name)
+	}
+	return names
+}
+
+// checkPreloads 检查预加载是否超出限制
+func (r *GenericRepo[T]) checkPreloads(ctx context.Context, preloads []string) error {
+	if r.MaxPreloads > 0 && len(preloads) > r.MaxPreloads {
+		return errspec.ErrTooManyPreloads.New(ctx)
+	}
+
+	if r.MaxPreloadDepth > 0 {
+		for _, preload := range preloads {
+			if strings.Count(preload, ".")+1 > r.MaxPreloadDepth {
+				return errspec.ErrTooManyPreloads.New(ctx)
+			}
+		}
+	}
+
+	return nil
+}
+
 // Get 根据ID或条件获取单个实体
 func (r *GenericRepo[T]) Get(ctx context.Context, id any, opts *QueryOptions) (*T, error) {
 	var entity T
 
 	// 创建查询并应用选项
-	query := r.applyQueryOptions(r.DB.WithContext(ctx), opts)
+	query := r.applyQueryOptions(r.withContext(ctx), opts)
 
 	// 执行查询
 	var err error
@@ -126,8 +489,74 @@ func (r *GenericRepo[T]) Get(ctx context.Context, id any, opts *QueryOptions) (*
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			// 使用ErrorCode创建特定的错误
-			return nil, errspec.ErrRecordNotExist.New(ctx).Wrap(err)
+			return nil, r.notFoundError(ctx, err)
+		}
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
+// GetByIDs 按ID批量获取实体，返回以主键值为键的映射
+// 映射的键为实体主键字段的值，类型与主键字段相同（如 uint），查找时需使用相同的类型。
+// 不存在的ID被忽略而不返回错误，ids 为空时直接返回空映射，不会查询数据库。
+func (r *GenericRepo[T]) GetByIDs(ctx context.Context, ids []any, opts *QueryOptions) (map[any]T, error) {
+	if len(ids) == 0 {
+		return map[any]T{}, nil
+	}
+
+	sch, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	var entities []T
+	query := r.applyQueryOptions(r.withContext(ctx), opts).
+		Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Values: ids})
+	if err := ignoreNotFound(query.Find(&entities).Error); err != nil {
+		return nil, err
+	}
+
+	result := make(map[any]T, len(entities))
+	for i := range entities {
+		key, _ := pk.ValueOf(ctx, reflect.ValueOf(&entities[i]).Elem())
+		result[key] = entities[i]
+	}
+
+	return result, nil
+}
+
+// GetForUpdate 根据ID或条件获取单个实体并加行锁（SELECT ... FOR UPDATE）
+// 行锁在事务结束时释放，脱离事务没有意义，因此仓库必须通过 WithTx 绑定事务。
+// SQLite 不支持行锁，会忽略 FOR UPDATE 子句。
+func (r *GenericRepo[T]) GetForUpdate(ctx context.Context, id any, opts *QueryOptions) (*T, error) {
+	if !inTransaction(r.DB) {
+		return nil, errspec.ErrNotInTransaction.New(ctx)
+	}
+
+	var entity T
+
+	// 创建查询并应用选项
+	query := r.applyQueryOptions(r.withContext(ctx), opts).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+
+	// 执行查询
+	var err error
+	if id != nil {
+		err = query.First(&entity, id).Error
+	} else if opts != nil && opts.Condition != "" {
+		err = query.First(&entity).Error
+	} else {
+		return nil, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, r.notFoundError(ctx, err)
 		}
 		return nil, err
 	}
@@ -135,15 +564,448 @@ func (r *GenericRepo[T]) Get(ctx context.Context, id any, opts *QueryOptions) (*
 	return &entity, nil
 }
 
+// GetExactlyOne 根据条件获取唯一的实体
+// 最多查询两条记录，用于发现本应唯一却存在重复的数据
+func (r *GenericRepo[T]) GetExactlyOne(ctx context.Context, opts *QueryOptions) (*T, error) {
+	if opts == nil || (opts.Condition == "" && len(opts.Opts) == 0 && len(opts.JSONConditions) == 0) {
+		return nil, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	var entities []T
+
+	// 创建查询并应用选项
+	query := r.applyQueryOptions(r.withContext(ctx), opts)
+
+	// 只需要两条记录即可判断是否唯一
+	if err := query.Limit(2).Find(&entities).Error; err != nil {
+		return nil, err
+	}
+
+	switch len(entities) {
+	case 0:
+		return nil, r.notFoundError(ctx, gorm.ErrRecordNotFound)
+	case 1:
+		return &entities[0], nil
+	default:
+		return nil, errspec.ErrMultipleRows.New(ctx)
+	}
+}
+
+// InsertMissing 批量插入键列尚不存在的实体，返回实际插入的数量
+// 使用 INSERT ... ON CONFLICT DO NOTHING 一次完成，keyColumns 上必须有唯一索引
+func (r *GenericRepo[T]) InsertMissing(ctx context.Context, entities []T, keyColumns []string) (int64, error) {
+	if len(entities) == 0 {
+		return 0, nil
+	}
+	if len(keyColumns) == 0 {
+		return 0, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	sch, err := r.parseSchema()
+	if err != nil {
+		return 0, err
+	}
+
+	columns := make([]clause.Column, 0, len(keyColumns))
+	for _, name := range keyColumns {
+		if _, ok := sch.FieldsByDBName[name]; !ok {
+			return 0, errspec.ErrInvalidColumn.New(ctx)
+		}
+		columns = append(columns, clause.Column{Name: name})
+	}
+
+	result := r.withContext(ctx).
+		Clauses(clause.OnConflict{Columns: columns, DoNothing: true}).
+		Create(&entities)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
 // Update 更新实体
+// 使用 Save 写入所有列，包括零值字段：只设置了部分字段的实体会把其他列清空。
+// 部分更新请使用 UpdateFields。
 func (r *GenericRepo[T]) Update(ctx context.Context, entity *T) error {
-	return r.DB.WithContext(ctx).Save(entity).Error
+	_, err := r.UpdateE(ctx, entity)
+	return err
+}
+
+// UpdateE 更新实体并返回影响的行数，与 Update 相同使用 Save 写入所有列
+// 注意 Save 在主键不存在时会插入新记录，此时同样返回 1；需要严格更新语义请使用 UpdateExisting。
+func (r *GenericRepo[T]) UpdateE(ctx context.Context, entity *T) (int64, error) {
+	result := r.withContext(ctx).Save(entity)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// UpdateExisting 按主键更新已存在的实体
+// 与 Update 相同会写入所有列，但只生成 UPDATE 语句：主键不存在或记录已被软删除时
+// 返回记录不存在错误，而不是像 Save 那样插入新记录。
+// 注意 MySQL 的影响行数不包含值未变化的行，实体没有 updated_at 列时重复更新相同的值也会返回记录不存在错误。
+func (r *GenericRepo[T]) UpdateExisting(ctx context.Context, entity *T) error {
+	sch, err := r.parseSchema()
+	if err != nil {
+		return err
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+	if _, zero := pk.ValueOf(ctx, reflect.ValueOf(entity).Elem()); zero {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	result := r.withContext(ctx).Model(entity).Select("*").Updates(entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.notFoundError(ctx, gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// CreateAndReload 创建实体后按主键重新查询
+// 数据库默认值、触发器等生成的列会回填到 entity 中，适用于需要返回完整资源的接口。
+// opts 用于重新查询时预加载关联，可以为nil。
+func (r *GenericRepo[T]) CreateAndReload(ctx context.Context, entity *T, opts *QueryOptions) error {
+	if err := r.Create(ctx, entity); err != nil {
+		return err
+	}
+	return r.reload(ctx, entity, opts)
+}
+
+// UpdateAndReload 保存实体后按主键重新查询，与 Update 相同会写入所有列
+// opts 用于重新查询时预加载关联，可以为nil。
+func (r *GenericRepo[T]) UpdateAndReload(ctx context.Context, entity *T, opts *QueryOptions) error {
+	if err := r.Update(ctx, entity); err != nil {
+		return err
+	}
+	return r.reload(ctx, entity, opts)
+}
+
+// reload 按主键从主库重新查询实体并覆盖 entity，避免读到尚未同步的只读副本
+func (r *GenericRepo[T]) reload(ctx context.Context, entity *T, opts *QueryOptions) error {
+	sch, err := r.parseSchema()
+	if err != nil {
+		return err
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	id, zero := pk.ValueOf(ctx, reflect.ValueOf(entity).Elem())
+	if zero {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	var reloaded T
+	err = r.applyQueryOptions(r.withContext(ctx).Clauses(dbresolver.Write), opts).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Value: id}).
+		First(&reloaded).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return r.notFoundError(ctx, err)
+		}
+		return err
+	}
+
+	*entity = reloaded
+	return nil
+}
+
+// UpdateFields 只更新指定的列，生成 UPDATE ... SET 语句，未列出的列保持不变
+// fields 的键可以是列名或字段名，值为零值时同样会写入
+func (r *GenericRepo[T]) UpdateFields(ctx context.Context, id any, fields map[string]any) error {
+	if id == nil || len(fields) == 0 {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	sch, err := r.parseSchema()
+	if err != nil {
+		return err
+	}
+	if sch.PrioritizedPrimaryField == nil {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+	for name := range fields {
+		if sch.LookUpField(name) == nil {
+			return errspec.ErrInvalidColumn.New(ctx)
+		}
+	}
+
+	var entity T
+	return r.withContext(ctx).Model(&entity).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sch.PrioritizedPrimaryField.DBName}, Value: id}).
+		Updates(fields).Error
+}
+
+// ToggleBool 翻转布尔列的值并返回翻转后的值
+// 使用 UPDATE ... SET col = NOT col 在数据库中完成翻转，避免先读后写的并发覆盖。
+// Postgres 上通过 UPDATE ... RETURNING 在一条语句中返回新值，其他数据库在同一事务中更新后读取；
+// 列不存在或不是布尔类型时返回 ErrInvalidColumn，
+// 记录不存在或已被软删除时返回记录不存在错误。列值为 NULL 时视为 false，翻转为 true。
+func (r *GenericRepo[T]) ToggleBool(ctx context.Context, id any, column string) (bool, error) {
+	if id == nil {
+		return false, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	sch, err := r.parseSchema()
+	if err != nil {
+		return false, err
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return false, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+	field := sch.LookUpField(column)
+	if field == nil || field.DBName == "" || field.DataType != schema.Bool {
+		return false, errspec.ErrInvalidColumn.New(ctx)
+	}
+
+	var value sql.NullBool
+	expr := gorm.Expr("NOT COALESCE(?, ?)", clause.Column{Name: field.DBName}, false)
+	if err := r.updateColumnExpr(ctx, pk, id, field, expr, &value); err != nil {
+		return false, err
+	}
+
+	return value.Bool, nil
+}
+
+// Increment 原子地为整数列增加 delta 并返回增加后的值，delta 为负数时减少
+// 使用 UPDATE ... SET col = col + ? 在数据库中完成计算，避免先读后写的并发覆盖。
+// Postgres 上通过 UPDATE ... RETURNING 在一条语句中返回新值，其他数据库在同一事务中更新后读取；
+// 列不存在或不是整数类型时返回 ErrInvalidColumn，
+// 记录不存在或已被软删除时返回记录不存在错误。
+func (r *GenericRepo[T]) Increment(ctx context.Context, id any, column string, delta int64) (int64, error) {
+	if id == nil {
+		return 0, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	sch, err := r.parseSchema()
+	if err != nil {
+		return 0, err
+	}
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return 0, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+	field := sch.LookUpField(column)
+	if field == nil || field.DBName == "" || (field.DataType != schema.Int && field.DataType != schema.Uint) {
+		return 0, errspec.ErrInvalidColumn.New(ctx)
+	}
+
+	var value sql.NullInt64
+	expr := gorm.Expr("? + ?", clause.Column{Name: field.DBName}, delta)
+	if err := r.updateColumnExpr(ctx, pk, id, field, expr, &value); err != nil {
+		return 0, err
+	}
+
+	return value.Int64, nil
+}
+
+// updateColumnExpr 按主键以表达式更新单列，并将更新后的值读取到 dest
+// Postgres 上使用 RETURNING 子句直接返回新值，其他数据库在事务中更新后重新读取
+func (r *GenericRepo[T]) updateColumnExpr(ctx context.Context, pk *schema.Field, id any, field *schema.Field, expr clause.Expr, dest sql.Scanner) error {
+	where := clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Value: id}
+
+	if r.DB.Dialector.Name() == "postgres" {
+		var entity T
+		result := r.withContext(ctx).Model(&entity).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: field.DBName}}}).
+			Where(where).
+			Update(field.DBName, expr)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return r.notFoundError(ctx, gorm.ErrRecordNotFound)
+		}
+
+		value := reflect.Indirect(field.ReflectValueOf(ctx, reflect.ValueOf(&entity).Elem()))
+		if !value.IsValid() {
+			return dest.Scan(nil)
+		}
+		return dest.Scan(value.Interface())
+	}
+
+	return r.Transaction(ctx, func(tx *gorm.DB) error {
+		var entity T
+
+		if err := tx.Model(&entity).Where(where).Update(field.DBName, expr).Error; err != nil {
+			return err
+		}
+
+		// MySQL 中值未变化的行不计入影响行数，因此以重新读取的结果判断记录是否存在
+		err := tx.Model(&entity).Select(field.DBName).Where(where).Row().Scan(dest)
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.notFoundError(ctx, gorm.ErrRecordNotFound)
+		}
+		return err
+	})
 }
 
 // Delete 删除实体
 func (r *GenericRepo[T]) Delete(ctx context.Context, id any) error {
+	_, err := r.DeleteE(ctx, id)
+	return err
+}
+
+// DeleteE 删除实体并返回影响的行数
+// 记录不存在时不返回错误，影响行数为 0，调用方可据此返回 404
+func (r *GenericRepo[T]) DeleteE(ctx context.Context, id any) (int64, error) {
 	var entity T
-	return r.DB.WithContext(ctx).Delete(&entity, id).Error
+	result := r.withContext(ctx).Delete(&entity, id)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteByIDs 按ID批量删除实体，返回实际删除的数量
+// 使用单条 DELETE ... WHERE id IN (...) 语句，实体支持软删除时执行软删除。
+// ids 为空时直接返回 0，不会删除任何记录。
+func (r *GenericRepo[T]) DeleteByIDs(ctx context.Context, ids []any) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	sch, err := r.parseSchema()
+	if err != nil {
+		return 0, err
+	}
+	if sch.PrioritizedPrimaryField == nil {
+		return 0, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	var entity T
+	result := r.withContext(ctx).
+		Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: sch.PrioritizedPrimaryField.DBName}, Values: ids}).
+		Delete(&entity)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteBy 软删除实体并记录删除人
+// 在同一条 UPDATE 语句中写入软删除列和 deleted_by 列，实体没有 deleted_by 列时忽略删除人。
+// 实体不支持软删除时返回 ErrSoftDeleteNotSupported，记录不存在或已被删除时返回记录不存在错误。
+func (r *GenericRepo[T]) DeleteBy(ctx context.Context, id any, deletedBy any) error {
+	sch, column, err := r.softDeleteColumn(ctx)
+	if err != nil {
+		return err
+	}
+	if sch.PrioritizedPrimaryField == nil {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	fields := map[string]any{column: r.DB.NowFunc()}
+	if field := sch.LookUpField("deleted_by"); field != nil && field.DBName != "" {
+		fields[field.DBName] = deletedBy
+	}
+
+	var entity T
+	result := r.withContext(ctx).Unscoped().Model(&entity).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sch.PrioritizedPrimaryField.DBName}, Value: id}).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: nil}).
+		Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.notFoundError(ctx, gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// parseSchema 解析实体的表结构
+func (r *GenericRepo[T]) parseSchema() (*schema.Schema, error) {
+	var entity T
+	stmt := &gorm.Statement{DB: r.DB}
+	if err := stmt.Parse(&entity); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// softDeleteColumn 获取实体的软删除列名
+// 软删除字段需声明为 gorm.DeletedAt，列名可通过 gorm:"column:removed_at" 自定义
+func (r *GenericRepo[T]) softDeleteColumn(ctx context.Context) (*schema.Schema, string, error) {
+	sch, err := r.parseSchema()
+	if err != nil {
+		return nil, "", err
+	}
+
+	deletedAtType := reflect.TypeOf(gorm.DeletedAt{})
+	for _, field := range sch.Fields {
+		if field.FieldType == deletedAtType && field.DBName != "" {
+			return sch, field.DBName, nil
+		}
+	}
+
+	return nil, "", errspec.ErrSoftDeleteNotSupported.New(ctx)
+}
+
+// Restore 恢复已软删除的实体
+func (r *GenericRepo[T]) Restore(ctx context.Context, id any) error {
+	sch, column, err := r.softDeleteColumn(ctx)
+	if err != nil {
+		return err
+	}
+	if sch.PrioritizedPrimaryField == nil {
+		return errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	var entity T
+	result := r.withContext(ctx).Unscoped().Model(&entity).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sch.PrioritizedPrimaryField.DBName}, Value: id}).
+		Where(clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: nil}).
+		Update(column, nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return r.notFoundError(ctx, gorm.ErrRecordNotFound)
+	}
+
+	return nil
+}
+
+// ListTrashed 获取已软删除的实体列表
+func (r *GenericRepo[T]) ListTrashed(ctx context.Context, page, pageSize int, opts *QueryOptions) ([]T, error) {
+	_, column, err := r.softDeleteColumn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []T
+
+	// 跳过默认的软删除过滤，只查询已删除的记录
+	query := r.withContext(ctx).Unscoped().
+		Where(clause.Neq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: nil})
+
+	// 应用分页
+	page, pageSize = r.normalizePage(page, pageSize)
+	offset := (page - 1) * pageSize
+	query = query.Offset(offset).Limit(pageSize)
+
+	// 应用查询选项
+	query = r.applyQueryOptions(query, opts)
+
+	if err := ignoreNotFound(query.Find(&entities).Error); err != nil {
+		return nil, err
+	}
+
+	return entities, nil
 }
 
 // List 获取实体列表
@@ -151,9 +1013,10 @@ func (r *GenericRepo[T]) List(ctx context.Context, page, pageSize int, opts *Que
 	var entities []T
 
 	// 创建查询
-	query := r.DB.WithContext(ctx)
+	query := r.withContext(ctx)
 
 	// 应用分页
+	page, pageSize = r.normalizePage(page, pageSize)
 	offset := (page - 1) * pageSize
 	query = query.Offset(offset).Limit(pageSize)
 
@@ -161,7 +1024,7 @@ func (r *GenericRepo[T]) List(ctx context.Context, page, pageSize int, opts *Que
 	query = r.applyQueryOptions(query, opts)
 
 	// 执行查询
-	if err := query.Find(&entities).Error; err != nil {
+	if err := ignoreNotFound(query.Find(&entities).Error); err != nil {
 		return nil, err
 	}
 
@@ -174,28 +1037,236 @@ func (r *GenericRepo[T]) Count(ctx context.Context, opts *QueryOptions) (int64,
 	var entity T
 
 	// 创建查询
-	query := r.DB.WithContext(ctx).Model(&entity)
+	query := r.withContext(ctx).Model(&entity)
+
+	// 去重由 COUNT(DISTINCT ...) 实现，不在查询上应用
+	var distinct bool
+	var distinctOn []string
+	if opts != nil && (opts.Distinct || len(opts.DistinctOn) > 0) {
+		distinct, distinctOn = opts.Distinct, opts.DistinctOn
+		o := *opts
+		o.Distinct, o.DistinctOn = false, nil
+		opts = &o
+	}
 
 	// 应用查询选项
 	query = r.applyQueryOptions(query, opts)
 
+	switch {
+	case opts != nil && len(opts.GroupBy) > 0:
+		// 分组查询统计分组数量，使用子查询保证结果为单个标量
+		query = r.withContext(ctx).
+			Table("(?) AS grouped", query.Select(strings.Join(opts.GroupBy, ", ")))
+	case len(distinctOn) > 0:
+		if query.Dialector.Name() != "postgres" {
+			return 0, errspec.ErrDistinctOnNotSupported.New(ctx)
+		}
+		err := query.Select("COUNT(DISTINCT (" + strings.Join(distinctOn, ", ") + "))").Scan(&count).Error
+		return count, ignoreNotFound(err)
+	case distinct:
+		// 按主键去重统计，与 List 去重后的行数一致
+		sch, err := r.parseSchema()
+		if err != nil {
+			return 0, err
+		}
+		if sch.PrioritizedPrimaryField == nil {
+			return 0, errspec.ErrQueryParamEmpty.New(ctx)
+		}
+		column := clause.Column{Table: r.tableName(query), Name: sch.PrioritizedPrimaryField.DBName}
+		err = query.Select("COUNT(DISTINCT ?)", column).Scan(&count).Error
+		return count, ignoreNotFound(err)
+	}
+
 	// 执行查询
-	if err := query.Count(&count).Error; err != nil {
+	if err := ignoreNotFound(query.Count(&count).Error); err != nil {
 		return 0, err
 	}
 
 	return count, nil
 }
 
+// sumQuery 创建对列求和的查询，没有匹配记录时结果为 0
+func (r *GenericRepo[T]) sumQuery(ctx context.Context, column string, opts *QueryOptions) (*gorm.DB, error) {
+	sch, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sch.FieldsByDBName[column]; !ok {
+		return nil, errspec.ErrInvalidColumn.New(ctx)
+	}
+
+	var entity T
+
+	// 创建查询并应用选项
+	query := r.applyQueryOptions(r.withContext(ctx).Model(&entity), opts)
+
+	col := clause.Column{Table: r.tableName(query), Name: column}
+	return query.Select("COALESCE(SUM(?), 0)", col), nil
+}
+
+// Sum 对列求和，结果为 float64
+// 浮点数存在精度误差，金额等要求精确的列请使用 SumDecimal
+func (r *GenericRepo[T]) Sum(ctx context.Context, column string, opts *QueryOptions) (float64, error) {
+	query, err := r.sumQuery(ctx, column, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	var sum float64
+	if err := ignoreNotFound(query.Scan(&sum).Error); err != nil {
+		return 0, err
+	}
+
+	return sum, nil
+}
+
+// SumDecimal 对列精确求和
+// 聚合结果以字符串形式读取后再转换为 decimal.Decimal，避免经过 float64 产生舍入误差
+func (r *GenericRepo[T]) SumDecimal(ctx context.Context, column string, opts *QueryOptions) (decimal.Decimal, error) {
+	query, err := r.sumQuery(ctx, column, opts)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	var sum sql.NullString
+	if err := query.Row().Scan(&sum); err != nil {
+		return decimal.Zero, err
+	}
+	if !sum.Valid || sum.String == "" {
+		return decimal.Zero, nil
+	}
+
+	return decimal.NewFromString(sum.String)
+}
+
+// CountByGroup 按列分组统计数量，返回以分组值（字符串形式）为键的映射
+// 一条 GROUP BY 查询代替 N 次 Count，column 必须是实体的列名，NULL 分组的键为空字符串
+func (r *GenericRepo[T]) CountByGroup(ctx context.Context, column string, opts *QueryOptions) (map[string]int64, error) {
+	sch, err := r.parseSchema()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := sch.FieldsByDBName[column]; !ok {
+		return nil, errspec.ErrInvalidColumn.New(ctx)
+	}
+
+	var entity T
+
+	// 创建查询并应用选项
+	query := r.applyQueryOptions(r.withContext(ctx).Model(&entity), opts)
+
+	col := clause.Column{Table: r.tableName(query), Name: column}
+	rows, err := query.Select("?, COUNT(*)", col).Group(query.Statement.Quote(col)).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]int64)
+	for rows.Next() {
+		var value any
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+
+		var key string
+		switch v := value.(type) {
+		case nil:
+		case []byte:
+			key = string(v)
+		default:
+			key = fmt.Sprint(v)
+		}
+		result[key] += count
+	}
+
+	return result, rows.Err()
+}
+
+// applyFilter 应用结构体条件
+// GORM 的结构体条件会忽略零值字段（0、""、false 等），
+// 需要按零值查询时，应在 fields 中列出对应的字段名或列名
+func applyFilter[T Entity](query *gorm.DB, filter *T, fields []string) *gorm.DB {
+	if filter == nil {
+		return query
+	}
+	if len(fields) == 0 {
+		return query.Where(filter)
+	}
+
+	args := make([]any, 0, len(fields))
+	for _, field := range fields {
+		args = append(args, field)
+	}
+	return query.Where(filter, args...)
+}
+
+// Find 以结构体的非零值字段作为等值条件分页查询，同时返回符合条件的总数
+// filter 为 nil 时查询全部记录。
+// 注意零值字段默认不参与条件，如 Status: 0 不会生成 status = 0，
+// 需要时通过 fields 指定，例如 Find(ctx, &User{Status: 0}, 1, 10, "Status")，
+// 此时只有 fields 中列出的字段作为条件。
+func (r *GenericRepo[T]) Find(ctx context.Context, filter *T, page, pageSize int, fields ...string) ([]T, int64, error) {
+	var entity T
+	var count int64
+
+	// 统计总数
+	if err := ignoreNotFound(applyFilter(r.withContext(ctx).Model(&entity), filter, fields).Count(&count).Error); err != nil {
+		return nil, 0, err
+	}
+	if count == 0 {
+		return []T{}, 0, nil
+	}
+
+	var entities []T
+
+	// 应用分页
+	page, pageSize = r.normalizePage(page, pageSize)
+	offset := (page - 1) * pageSize
+	query := applyFilter(r.withContext(ctx), filter, fields).Offset(offset).Limit(pageSize)
+
+	if err := ignoreNotFound(query.Find(&entities).Error); err != nil {
+		return nil, 0, err
+	}
+
+	return entities, count, nil
+}
+
+// FindOne 以结构体的非零值字段作为等值条件获取单个实体
+// 零值字段的处理与 Find 相同，filter 为 nil 时返回 ErrQueryParamEmpty
+func (r *GenericRepo[T]) FindOne(ctx context.Context, filter *T, fields ...string) (*T, error) {
+	if filter == nil {
+		return nil, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+
+	var entity T
+	if err := applyFilter(r.withContext(ctx), filter, fields).First(&entity).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, r.notFoundError(ctx, err)
+		}
+		return nil, err
+	}
+
+	return &entity, nil
+}
+
 // Transaction 在事务中执行函数
 func (r *GenericRepo[T]) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
-	return r.DB.WithContext(ctx).Transaction(fn)
+	return r.withContext(ctx).Transaction(fn)
 }
 
 // WithTx 使用事务
+// 返回的仓库保留开启事务时的上下文，事务内的方法即使传入 context.Background()，
+// 日志中仍包含请求ID、链路追踪ID等关联字段
 func (r *GenericRepo[T]) WithTx(tx *gorm.DB) Repository[T] {
 	return &GenericRepo[T]{
-		DB:        tx,
-		ErrorCode: r.ErrorCode,
+		DB:              tx,
+		NotFoundErr:     r.NotFoundErr,
+		MaxPreloads:     r.MaxPreloads,
+		MaxPreloadDepth: r.MaxPreloadDepth,
+		MaxPageSize:     r.MaxPageSize,
+		txCtx:           tx.Statement.Context,
+		debugSQL:        r.debugSQL,
 	}
 }
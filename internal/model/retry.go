@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// 事务重试的退避参数
+const (
+	retryBaseDelay = 50 * time.Millisecond // 首次重试前的等待时间
+	retryMaxDelay  = 2 * time.Second       // 单次等待的上限
+)
+
+// DefaultSerializableRetries 串行化隔离级别的事务遇到序列化失败时的默认重试次数
+var DefaultSerializableRetries = 3
+
+// TransactionWithRetry 在事务中执行函数，遇到死锁或序列化失败时重试
+// 重试前按指数退避加随机抖动等待，ctx 取消时立即返回。
+// 只有可安全重试的错误才会重试，其他错误直接返回；fn 可能被执行多次，不应包含事务外的副作用。
+func (r *GenericRepo[T]) TransactionWithRetry(ctx context.Context, maxRetries int, fn func(tx *gorm.DB) error) error {
+	return r.retryTransaction(ctx, maxRetries, nil, fn)
+}
+
+// TransactionWithOptions 使用指定的隔离级别和只读选项开启事务并执行函数
+// 隔离级别为 sql.LevelSerializable 时，遇到序列化失败或死锁自动重试，最多 DefaultSerializableRetries 次，
+// 此时 fn 可能被执行多次，不应包含事务外的副作用。
+// 已在事务中（仓库通过 WithTx 绑定事务）时以保存点嵌套执行，选项不生效，也不会重试，错误交由外层事务处理。
+func (r *GenericRepo[T]) TransactionWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(tx *gorm.DB) error) error {
+	maxRetries := 0
+	if opts != nil && opts.Isolation == sql.LevelSerializable && !inTransaction(r.DB) {
+		maxRetries = DefaultSerializableRetries
+	}
+	return r.retryTransaction(ctx, maxRetries, opts, fn)
+}
+
+// retryTransaction 在事务中执行函数，遇到可重试的错误时最多重试 maxRetries 次
+func (r *GenericRepo[T]) retryTransaction(ctx context.Context, maxRetries int, opts *sql.TxOptions, fn func(tx *gorm.DB) error) error {
+	for attempt := 0; ; attempt++ {
+		err := r.withContext(ctx).Transaction(fn, opts)
+		if err == nil || attempt >= maxRetries || !IsRetryableError(err) {
+			return err
+		}
+
+		timer := time.NewTimer(retryDelay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay 计算第 attempt 次重试前的等待时间
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay << attempt
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	// 加入随机抖动，避免冲突的事务同时重试再次冲突
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// IsRetryableError 判断数据库错误是否可以通过重试事务解决
+//   - MySQL: 1213 死锁、1205 锁等待超时
+//   - Postgres: 40001 序列化失败、40P01 死锁
+//   - SQLite: 数据库被锁定
+func IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213 || mysqlErr.Number == 1205
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "SQLITE_BUSY")
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"context"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateTempTable 将查询结果物化为临时表，并返回绑定到该临时表的仓库
+// 适用于多步骤报表等需要反复查询中间结果的场景，避免重复计算昂贵的子查询。
+//
+// 临时表只在创建它的数据库连接内可见，因此必须在事务中调用，
+// 并且后续查询都要使用同一个事务 tx：
+//   - Postgres: 使用 ON COMMIT DROP，事务提交或回滚后自动删除
+//   - MySQL: 临时表存活到连接关闭，连接会被连接池复用，用完后应调用 DropTempTable
+//   - SQLite: 与 MySQL 相同，临时表存活到连接关闭
+//
+// T 为临时表的行类型，其字段需与 query 选出的列对应。
+func CreateTempTable[T Entity](ctx context.Context, tx *gorm.DB, name string, query *gorm.DB) (*GenericRepo[T], error) {
+	if name == "" || query == nil {
+		return nil, errspec.ErrQueryParamEmpty.New(ctx)
+	}
+	if !inTransaction(tx) {
+		return nil, errspec.ErrNotInTransaction.New(ctx)
+	}
+
+	sql := "CREATE TEMPORARY TABLE ? AS ?"
+	if tx.Dialector.Name() == "postgres" {
+		sql = "CREATE TEMPORARY TABLE ? ON COMMIT DROP AS ?"
+	}
+
+	if err := tx.WithContext(ctx).Exec(sql, clause.Table{Name: name}, query).Error; err != nil {
+		return nil, err
+	}
+
+	repo := NewGenericRepo[T](tx.Table(name).Session(&gorm.Session{}))
+	return repo, nil
+}
+
+// DropTempTable 删除临时表
+func DropTempTable(ctx context.Context, tx *gorm.DB, name string) error {
+	if tx.Dialector.Name() == "mysql" {
+		return tx.WithContext(ctx).Exec("DROP TEMPORARY TABLE IF EXISTS ?", clause.Table{Name: name}).Error
+	}
+	return tx.WithContext(ctx).Exec("DROP TABLE IF EXISTS ?", clause.Table{Name: name}).Error
+}
+
+// inTransaction 判断 db 是否处于事务中
+func inTransaction(db *gorm.DB) bool {
+	if db == nil || db.Statement == nil {
+		return false
+	}
+	_, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
+}
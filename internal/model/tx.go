@@ -0,0 +1,27 @@
+package model
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// RunInTx 在事务中执行函数并返回计算结果
+// fn 返回 nil 时提交事务并返回结果；fn 返回错误或提交失败时回滚事务，
+// 此时始终返回 R 的零值，即使 fn 同时返回了非零值。
+func RunInTx[R any](ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB) (R, error)) (R, error) {
+	var result R
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		value, err := fn(tx)
+		if err != nil {
+			return err
+		}
+		result = value
+		return nil
+	})
+	if err != nil {
+		var zero R
+		return zero, err
+	}
+	return result, nil
+}
@@ -51,8 +51,7 @@ type UserRepo struct {
 
 // NewUserRepo 创建用户仓库
 func NewUserRepo(db *gorm.DB) *UserRepo {
-	genericRepo := NewGenericRepo[User](db)
-	genericRepo.ErrorCode = errspec.ErrUserNotFound.Code()
+	genericRepo := NewGenericRepoWithNotFound[User](db, errspec.ErrUserNotFound)
 
 	return &UserRepo{
 		GenericRepo: genericRepo,
@@ -0,0 +1,53 @@
+// Package ctxutil 提供在 context 中传递请求级控制参数的工具函数
+package ctxutil
+
+import "context"
+
+// readFromPrimaryKey 强制读主库的上下文键
+type readFromPrimaryKey struct{}
+
+// ReadFromPrimary 返回强制读主库的上下文
+// 开启读写分离后，仓库的读方法默认走只读副本；写入后需要立即读到最新数据时，
+// 使用该上下文让读操作走主库，避免主从复制延迟导致读到旧数据
+func ReadFromPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readFromPrimaryKey{}, true)
+}
+
+// IsReadFromPrimary 判断上下文是否要求读主库
+func IsReadFromPrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(readFromPrimaryKey{}).(bool)
+	return v
+}
+
+// requestIDKey 请求ID的上下文键，与 RequestID 中间件写入的键一致
+const requestIDKey = "request_id"
+
+// RequestID 获取上下文中的请求ID，不存在时返回空字符串
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// fallbackContext 值查找可以回退到另一个上下文的上下文
+type fallbackContext struct {
+	context.Context
+	fallback context.Context
+}
+
+// Value 优先从自身查找，找不到时从 fallback 中查找
+func (c fallbackContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.fallback.Value(key)
+}
+
+// WithFallback 返回值查找可以回退到 fallback 的上下文
+// 取消和超时仍由 ctx 决定，ctx 中没有的值（如请求ID、链路追踪信息）从 fallback 中查找。
+// 用于在事务内的调用传入了不含请求信息的上下文时，保留开启事务时的日志关联字段
+func WithFallback(ctx, fallback context.Context) context.Context {
+	if fallback == nil || ctx == fallback {
+		return ctx
+	}
+	return fallbackContext{Context: ctx, fallback: fallback}
+}
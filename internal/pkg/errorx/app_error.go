@@ -12,6 +12,7 @@ import (
 type AppError struct {
 	error
 	message    string
+	original   string // 被 WithMessage 替换前的消息
 	code       int    // 错误码
 	httpStatus int    // HTTP状态码
 	traceID    string // 链路追踪 ID
@@ -59,10 +60,35 @@ func (e *AppError) HttpStatus() int {
 }
 
 func (e *AppError) WithMessage(msg string) *AppError {
+	e.keepOriginal()
 	e.message = msg
 	return e
 }
 
+// WithMessagef 使用格式化的消息替换错误消息，错误码和HTTP状态码保持不变
+// 如 ErrRecordNotExist.New(ctx).WithMessagef("user %d not found", id)
+func (e *AppError) WithMessagef(format string, args ...any) *AppError {
+	return e.WithMessage(fmt.Sprintf(format, args...))
+}
+
+// OriginalMessage 获取错误定义的原始消息，不受 WithMessage 影响
+func (e *AppError) OriginalMessage() string {
+	if e.error != nil {
+		return e.error.Error()
+	}
+	if e.original != "" {
+		return e.original
+	}
+	return e.message
+}
+
+// keepOriginal 首次替换消息前保存原始消息
+func (e *AppError) keepOriginal() {
+	if e.error == nil && e.original == "" {
+		e.original = e.message
+	}
+}
+
 // TraceID 获取链路追踪 ID
 func (e *AppError) TraceID() string {
 	return e.traceID
@@ -138,6 +164,11 @@ func FormatErrorChain(err error) string {
 		return ""
 	}
 
+	// AppError 的 Error 只返回自身消息，逐层拼接原始错误
+	if e, ok := err.(*AppError); ok && e.cause != nil {
+		return e.Error() + ": " + FormatErrorChain(e.cause)
+	}
+
 	// 使用 %+v 格式化错误，包含堆栈跟踪
 	return fmt.Sprintf("%+v", err)
 }
@@ -6,10 +6,12 @@ import (
 )
 
 func Definef[Args any](i18n *i18n.I18n, code int, format string, httpStatus int) *i18nerrx.Definition[*AppError, Args] {
+	Register(Spec{Code: code, Message: format, HttpStatus: httpStatus})
 	return i18nerrx.Definef[Args](i18n, format, wrapAppError(code, httpStatus))
 }
 
 func Define(i18n *i18n.I18n, code int, format string, httpStatus int) *i18nerrx.DefinitionSimple[*AppError] {
+	Register(Spec{Code: code, Message: format, HttpStatus: httpStatus})
 	return i18nerrx.Define(i18n, format, wrapAppError(code, httpStatus))
 }
 
@@ -0,0 +1,60 @@
+package errorx
+
+import (
+	"slices"
+	"sync"
+)
+
+// Spec 错误定义，由 Define 和 Definef 自动注册
+type Spec struct {
+	Code       int    // 错误码
+	Message    string // 默认消息（翻译前的消息模板）
+	HttpStatus int    // HTTP状态码
+}
+
+var (
+	specs   = make(map[int]Spec)
+	specsMu sync.RWMutex
+)
+
+// Register 注册错误定义，相同错误码重复注册时覆盖之前的定义
+// 通过 Define 和 Definef 定义的错误会自动注册，只有不使用这两个函数定义的错误码需要手动注册
+func Register(spec Spec) {
+	specsMu.Lock()
+	defer specsMu.Unlock()
+
+	specs[spec.Code] = spec
+}
+
+// Unregister 移除错误码的注册，错误码未注册时不做任何操作
+// 主要用于测试中清理手动注册的错误定义
+func Unregister(code int) {
+	specsMu.Lock()
+	defer specsMu.Unlock()
+
+	delete(specs, code)
+}
+
+// LookupSpec 按错误码查找错误定义
+func LookupSpec(code int) (Spec, bool) {
+	specsMu.RLock()
+	defer specsMu.RUnlock()
+
+	spec, ok := specs[code]
+	return spec, ok
+}
+
+// Specs 获取所有已注册的错误定义，按错误码排序
+func Specs() []Spec {
+	specsMu.RLock()
+	defer specsMu.RUnlock()
+
+	result := make([]Spec, 0, len(specs))
+	for _, spec := range specs {
+		result = append(result, spec)
+	}
+	slices.SortFunc(result, func(a, b Spec) int {
+		return a.Code - b.Code
+	})
+	return result
+}
@@ -0,0 +1,48 @@
+package errorx
+
+import "errors"
+
+// coder 携带错误码的错误
+type coder interface {
+	error
+	Code() int
+}
+
+// NearestError 沿错误链查找最近的携带错误码的错误，找不到时返回 nil
+// 与 errors.As 的语义一致，外层业务错误有意转换的错误码（如 ErrInternal.Wrap(dbErr)）不会被内层覆盖
+func NearestError(err error) error {
+	var e coder
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
+
+// NearestCode 沿错误链查找最近的携带错误码的错误的错误码
+func NearestCode(err error) (int, bool) {
+	if e := NearestError(err); e != nil {
+		return e.(coder).Code(), true
+	}
+	return 0, false
+}
+
+// RootError 沿错误链查找最深层携带错误码的错误，找不到时返回 nil
+// 错误被多层包装后，顶层错误可能不再携带错误码，此时可以取回最初的业务错误
+func RootError(err error) error {
+	var root error
+	for err != nil {
+		if e, ok := err.(coder); ok {
+			root = e
+		}
+		err = errors.Unwrap(err)
+	}
+	return root
+}
+
+// RootCode 沿错误链查找最深层错误的错误码
+func RootCode(err error) (int, bool) {
+	if root := RootError(err); root != nil {
+		return root.(coder).Code(), true
+	}
+	return 0, false
+}
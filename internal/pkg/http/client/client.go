@@ -78,9 +78,10 @@ func doRequest(ctx context.Context, method, url string, body io.Reader, opts ...
 		req.Header.Set(k, v)
 	}
 
-	// 创建客户端
+	// 创建客户端，自动传递上下文中的请求ID
 	client := &http.Client{
-		Timeout: o.timeout,
+		Timeout:   o.timeout,
+		Transport: NewRequestIDTransport(nil),
 	}
 
 	// 发送请求
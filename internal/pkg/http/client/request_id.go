@@ -0,0 +1,45 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/limitcool/starter/internal/pkg/ctxutil"
+)
+
+// HeaderRequestID 默认传递请求ID的请求头
+const HeaderRequestID = "X-Request-ID"
+
+// RequestIDTransport 传递请求ID的 RoundTripper
+// 从出站请求的 context 中读取 RequestID 中间件写入的请求ID并设置到请求头，
+// 使下游服务的日志能与当前请求关联。请求已设置该请求头或上下文中没有请求ID时不做修改。
+type RequestIDTransport struct {
+	Base   http.RoundTripper // 实际发送请求的 RoundTripper，为 nil 时使用 http.DefaultTransport
+	Header string            // 请求头名称，为空时使用 X-Request-ID
+}
+
+// NewRequestIDTransport 创建传递请求ID的 RoundTripper，包装已有的 base
+func NewRequestIDTransport(base http.RoundTripper) *RequestIDTransport {
+	return &RequestIDTransport{Base: base, Header: HeaderRequestID}
+}
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	header := t.Header
+	if header == "" {
+		header = HeaderRequestID
+	}
+
+	requestID := ctxutil.RequestID(req.Context())
+	if requestID == "" || req.Header.Get(header) != "" {
+		return base.RoundTrip(req)
+	}
+
+	// RoundTripper 不能修改传入的请求，复制后再设置请求头
+	req = req.Clone(req.Context())
+	req.Header.Set(header, requestID)
+	return base.RoundTrip(req)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/limitcool/starter/pkg/logconfig"
 )
 
 // CharmLogger 基于 charmbracelet/log 的日志实现
@@ -16,8 +17,39 @@ type CharmLogger struct {
 	format Format
 }
 
-// NewCharmLogger 创建一个新的 CharmLogger
+const (
+	// DefaultCharmPrefix 未配置前缀和服务名时 CharmLogger 使用的默认前缀
+	DefaultCharmPrefix = "🌏 starter"
+	// ServiceKey 服务名在日志中的字段名
+	ServiceKey = "service"
+)
+
+// NewCharmLogger 创建一个新的 CharmLogger，使用默认前缀
 func NewCharmLogger(w io.Writer, level Level, format Format) *CharmLogger {
+	return newCharmLogger(w, level, format, DefaultCharmPrefix)
+}
+
+// NewCharmLoggerWithConfig 使用配置创建一个新的 CharmLogger
+// 前缀取 Prefix，其次 ServiceName，都未配置时使用 DefaultCharmPrefix；配置了 ServiceName 时每条日志附带 service 字段。
+func NewCharmLoggerWithConfig(w io.Writer, config logconfig.LogConfig) *CharmLogger {
+	format := TextFormat
+	if config.Format == logconfig.LogFormatJSON {
+		format = JSONFormat
+	}
+	prefix := config.LogPrefix()
+	if prefix == "" {
+		prefix = DefaultCharmPrefix
+	}
+
+	l := newCharmLogger(w, parseLogLevel(config.Level), format, prefix)
+	if config.ServiceName != "" {
+		l.logger = l.logger.With(ServiceKey, config.ServiceName)
+	}
+	return l
+}
+
+// newCharmLogger 创建使用指定前缀的 CharmLogger
+func newCharmLogger(w io.Writer, level Level, format Format, prefix string) *CharmLogger {
 	if w == nil {
 		w = os.Stdout
 	}
@@ -25,7 +57,7 @@ func NewCharmLogger(w io.Writer, level Level, format Format) *CharmLogger {
 	// 创建 charmbracelet/log 的 Logger
 	options := log.Options{
 		Level:           convertToCharmLevel(level),
-		Prefix:          "🌏 starter",
+		Prefix:          prefix,
 		TimeFormat:      time.RFC3339,
 		ReportTimestamp: true,
 		ReportCaller:    level == DebugLevel,
@@ -159,11 +191,12 @@ func (l *CharmLogger) WithContext(ctx context.Context) Logger {
 	// 创建新的 Logger
 	newLogger := l.logger.With(keyValues...)
 
-	return &CharmLogger{
+	// 上下文中设置了日志级别时使用该级别
+	return applyContextLevel(ctx, &CharmLogger{
 		logger: newLogger,
 		level:  l.level,
 		format: l.format,
-	}
+	})
 }
 
 // DebugContext 实现 Logger 接口
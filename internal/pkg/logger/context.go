@@ -0,0 +1,34 @@
+package logger
+
+import "context"
+
+// contextKey 日志记录器在 context.Context 中的键
+type contextKey struct{}
+
+// NewContext 返回携带日志记录器的新上下文
+// 之后通过 FromContext 或包级的 XxxContext 函数记录日志时使用该日志记录器
+func NewContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext 获取上下文中的日志记录器
+// 上下文中没有日志记录器时，返回附加了上下文中请求ID、链路追踪ID等字段的默认日志记录器
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := loggerFromContext(ctx); ok {
+		return logger
+	}
+	return Default().WithContext(ctx)
+}
+
+// loggerFromContext 获取通过 NewContext 保存的日志记录器
+// 上下文中设置了日志级别时返回使用该级别的副本
+func loggerFromContext(ctx context.Context) (Logger, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	logger, ok := ctx.Value(contextKey{}).(Logger)
+	if !ok {
+		return nil, false
+	}
+	return applyContextLevel(ctx, logger), true
+}
@@ -70,3 +70,28 @@ func SetupGinLogger() {
 	// 将Gin的错误输出重定向到我们的Logger (Error级别)
 	gin.DefaultErrorWriter = NewGinLogWriter(logger, ErrorLevel)
 }
+
+// GinContextKey 请求级日志记录器在 gin 上下文中的键
+const GinContextKey = "starter:logger"
+
+// SetGin 将请求级日志记录器保存到 gin 上下文
+func SetGin(c *gin.Context, logger Logger) {
+	c.Set(GinContextKey, logger)
+}
+
+// FromGin 获取请求级日志记录器
+// 未通过 SetGin 设置时，回退为请求上下文中的日志记录器
+func FromGin(c *gin.Context) Logger {
+	if v, ok := c.Get(GinContextKey); ok {
+		if logger, ok := v.(Logger); ok {
+			if c.Request != nil {
+				return applyContextLevel(c.Request.Context(), logger)
+			}
+			return logger
+		}
+	}
+	if c.Request != nil {
+		return FromContext(c.Request.Context())
+	}
+	return Default()
+}
@@ -2,14 +2,8 @@
 package logger
 
 import (
-	"bytes"
 	"context"
-	"fmt"
 	"io"
-	"strings"
-
-	"github.com/limitcool/starter/internal/pkg/errorx"
-	"github.com/pkg/errors"
 )
 
 // Level 日志级别
@@ -126,6 +120,23 @@ func SetDefault(logger Logger) {
 	global = logger
 }
 
+// Sync 将默认日志记录器缓冲的日志写入输出
+func Sync() error {
+	if s, ok := Default().(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close 刷新并关闭默认日志记录器的文件输出
+// 应在程序退出前调用，如 defer logger.Close()，避免丢失最后写入的日志
+func Close() error {
+	if c, ok := Default().(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // Debug 使用默认日志记录器记录调试级别日志
 func Debug(msg string, keysAndValues ...any) {
 	Default().Debug(msg, keysAndValues...)
@@ -209,21 +220,37 @@ func LogDebugContext(ctx context.Context, msg string, keysAndValues ...any) {
 
 // DebugContext 使用上下文记录调试级别日志
 func DebugContext(ctx context.Context, msg string, keysAndValues ...any) {
+	if logger, ok := loggerFromContext(ctx); ok {
+		logger.Debug(msg, keysAndValues...)
+		return
+	}
 	Default().DebugContext(ctx, msg, keysAndValues...)
 }
 
 // InfoContext 使用上下文记录信息级别日志
 func InfoContext(ctx context.Context, msg string, keysAndValues ...any) {
+	if logger, ok := loggerFromContext(ctx); ok {
+		logger.Info(msg, keysAndValues...)
+		return
+	}
 	Default().InfoContext(ctx, msg, keysAndValues...)
 }
 
 // WarnContext 使用上下文记录警告级别日志
 func WarnContext(ctx context.Context, msg string, keysAndValues ...any) {
+	if logger, ok := loggerFromContext(ctx); ok {
+		logger.Warn(msg, keysAndValues...)
+		return
+	}
 	Default().WarnContext(ctx, msg, keysAndValues...)
 }
 
 // ErrorContext 使用上下文记录错误级别日志
 func ErrorContext(ctx context.Context, msg string, keysAndValues ...any) {
+	if logger, ok := loggerFromContext(ctx); ok {
+		logger.Error(msg, keysAndValues...)
+		return
+	}
 	Default().ErrorContext(ctx, msg, keysAndValues...)
 }
 
@@ -239,61 +266,5 @@ func WithContext(ctx context.Context) Logger {
 
 // LogErrorWithContext 记录错误日志，包含错误详情和堆栈信息，并使用上下文
 func LogErrorWithContext(ctx context.Context, msg string, err error, keyvals ...any) {
-	// 构建日志字段
-	fields := make([]any, 0, len(keyvals)+4) // 预分配空间
-
-	// 检查是否需要显示堆栈
-	showStackTrace := ShouldShowStackTrace(ErrorLevel)
-
-	// 判断错误类型并处理
-	if err != nil {
-		// 添加基本错误信息
-		fields = append(fields, "error", err.Error())
-
-		// 处理 AppError 类型
-		if appErr, ok := err.(*errorx.AppError); ok {
-			// 添加错误码
-			fields = append(fields, "error_code", appErr.Code())
-
-			// 添加错误链
-			errorChain := fmt.Sprintf("%+v", appErr)
-			if errorChain != "" {
-				fields = append(fields, "error_chain", errorChain)
-
-				// 如果需要显示堆栈，并且错误中没有包含堆栈信息，则尝试添加
-				if showStackTrace && !strings.Contains(errorChain, "[") {
-					// 尝试获取和添加堆栈信息
-					if formatter, ok := err.(fmt.Formatter); ok {
-						var buf bytes.Buffer
-						_, _ = fmt.Fprintf(&buf, "%+v", formatter)
-						fields = append(fields, "stack_trace", "\n"+buf.String())
-					}
-				}
-			}
-		} else {
-			// 非 AppError 类型，如果需要显示堆栈，则尝试添加
-			if showStackTrace {
-				// 检查是否是 pkg/errors 类型的错误
-				var pkgErr interface{ StackTrace() errors.StackTrace }
-				if errors.As(err, &pkgErr) {
-					var buf bytes.Buffer
-					_, _ = fmt.Fprintf(&buf, "%+v", pkgErr.StackTrace())
-					fields = append(fields, "stack_trace", "\n"+buf.String())
-				} else {
-					// 尝试获取和添加堆栈信息
-					if formatter, ok := err.(fmt.Formatter); ok {
-						var buf bytes.Buffer
-						_, _ = fmt.Fprintf(&buf, "%+v", formatter)
-						fields = append(fields, "stack_trace", "\n"+buf.String())
-					}
-				}
-			}
-		}
-	}
-
-	// 添加额外的字段
-	fields = append(fields, keyvals...)
-
-	// 记录错误
-	ErrorContext(ctx, msg, fields...)
+	LogErrorLevelContext(ctx, ErrorLevel, msg, err, keyvals...)
 }
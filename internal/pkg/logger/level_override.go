@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelContextKey 请求级日志级别在 context.Context 中的键
+type levelContextKey struct{}
+
+// NewLevelContext 返回携带日志级别的新上下文
+// 之后通过该上下文记录的日志使用此级别过滤，而不是全局级别，用于单独调试某个请求
+func NewLevelContext(ctx context.Context, level Level) context.Context {
+	return context.WithValue(ctx, levelContextKey{}, level)
+}
+
+// LevelFromContext 获取上下文中的日志级别
+func LevelFromContext(ctx context.Context) (Level, bool) {
+	if ctx == nil {
+		return InfoLevel, false
+	}
+	level, ok := ctx.Value(levelContextKey{}).(Level)
+	return level, ok
+}
+
+// levelOverrider 支持创建不同日志级别副本的日志记录器
+type levelOverrider interface {
+	WithLevel(level Level) Logger
+}
+
+// applyContextLevel 上下文中设置了日志级别时，返回使用该级别的日志记录器
+// 日志记录器不支持修改级别时原样返回
+func applyContextLevel(ctx context.Context, logger Logger) Logger {
+	level, ok := LevelFromContext(ctx)
+	if !ok {
+		return logger
+	}
+	return applyLevel(logger, level)
+}
+
+// levelCore 按级别过滤日志的 core
+// 实际输出的 core 以 debug 级别创建，由外层的 levelCore 决定生效的级别，
+// 修改级别时只需替换外层，不必重新创建输出
+type levelCore struct {
+	zapcore.Core
+	level zapcore.Level
+}
+
+// Enabled 实现 zapcore.Core 接口
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+// Level 返回生效的最低日志级别
+func (c *levelCore) Level() zapcore.Level {
+	return c.level
+}
+
+// With 实现 zapcore.Core 接口
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check 实现 zapcore.Core 接口
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// withZapLevel 返回替换 levelCore 级别的 zap 选项
+func withZapLevel(level Level) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if c, ok := core.(*levelCore); ok {
+			return &levelCore{Core: c.Core, level: convertToZapLevel(level)}
+		}
+		return core
+	})
+}
+
+// WithLevel 返回使用指定日志级别的副本，不影响原日志记录器
+func (l *ZapLogger) WithLevel(level Level) Logger {
+	clone := *l
+	clone.structLogger = l.structLogger.WithOptions(withZapLevel(level))
+	clone.logger = l.logger.WithOptions(withZapLevel(level))
+	clone.level = level
+	return &clone
+}
+
+// WithLevel 返回使用指定日志级别的副本，采样状态与原日志记录器共享
+func (l *SamplingLogger) WithLevel(level Level) Logger {
+	return &SamplingLogger{Logger: applyLevel(l.Logger, level), sampler: l.sampler}
+}
+
+// WithLevel 返回使用指定日志级别的副本，不影响原日志记录器
+func (l *CharmLogger) WithLevel(level Level) Logger {
+	child := l.logger.With()
+	child.SetLevel(convertToCharmLevel(level))
+	return &CharmLogger{
+		logger: child,
+		level:  level,
+		format: l.format,
+	}
+}
+
+// applyLevel 日志记录器支持修改级别时返回指定级别的副本
+func applyLevel(logger Logger, level Level) Logger {
+	if o, ok := logger.(levelOverrider); ok {
+		return o.WithLevel(level)
+	}
+	return logger
+}
@@ -1,9 +1,7 @@
 package logger
 
 import (
-	"bytes"
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/limitcool/starter/internal/pkg/errorx"
@@ -11,8 +9,59 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrUnknownLevel 无法识别的日志级别
+var ErrUnknownLevel = errors.New("unknown log level")
+
 // Setup 初始化日志配置
+// 日志级别无法识别时回退到 info 并记录一条警告
 func Setup(config logconfig.LogConfig) {
+	setup(config)
+
+	if err := validateLevels(config); err != nil {
+		Default().Warn("Invalid log level in config, falling back to default", "error", err)
+	}
+}
+
+// SetupE 初始化日志配置，日志级别无法识别时返回错误且不修改当前日志记录器
+func SetupE(config logconfig.LogConfig) error {
+	if err := validateLevels(config); err != nil {
+		return err
+	}
+
+	setup(config)
+	return nil
+}
+
+// validateLevels 校验配置中的日志级别，未配置的级别视为使用默认值
+func validateLevels(config logconfig.LogConfig) error {
+	if config.Level != "" {
+		if _, err := ParseLevel(string(config.Level)); err != nil {
+			return err
+		}
+	}
+
+	if config.StackTraceLevel != "" {
+		if _, err := ParseLevel(string(config.StackTraceLevel)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// New 根据配置创建日志记录器，不修改默认日志记录器和全局堆栈跟踪配置
+// 适用于需要显式注入日志记录器、或在同一进程中使用多个相互隔离的日志记录器的场景，
+// 日志级别无法识别时返回错误
+func New(config logconfig.LogConfig) (Logger, error) {
+	if err := validateLevels(config); err != nil {
+		return nil, err
+	}
+
+	return newLogger(config), nil
+}
+
+// setup 根据配置创建并设置默认日志记录器
+func setup(config logconfig.LogConfig) {
 	// 更新堆栈跟踪配置
 	UpdateStackTraceConfig(
 		config.StackTraceEnabled,
@@ -20,28 +69,52 @@ func Setup(config logconfig.LogConfig) {
 		config.MaxStackFrames,
 	)
 
-	// 创建并设置logger
+	SetDefault(newLogger(config))
+}
+
+// newLogger 根据配置创建日志记录器
+func newLogger(config logconfig.LogConfig) Logger {
 	// 使用ZapLogger代替CharmLogger以提高性能
-	logger := NewZapLoggerWithConfig(config)
-	SetDefault(logger)
+	var logger Logger = NewZapLoggerWithConfig(config)
+
+	// 启用采样时包装一层采样日志记录器，抑制高频重复日志
+	if config.Sampling {
+		logger = NewSamplingLogger(logger, SamplingOptions{
+			Initial:    config.SamplingInitial,
+			Thereafter: config.SamplingThereafter,
+			Interval:   config.SamplingInterval,
+		})
+	}
+
+	return logger
 }
 
-// parseLogLevel 解析日志级别
-func parseLogLevel(level logconfig.LogLevel) Level {
-	switch level {
-	case logconfig.LogLevelDebug:
-		return DebugLevel
-	case logconfig.LogLevelInfo:
-		return InfoLevel
-	case logconfig.LogLevelWarn:
-		return WarnLevel
-	case logconfig.LogLevelError:
-		return ErrorLevel
-	case logconfig.LogLevelFatal:
-		return FatalLevel
+// ParseLevel 解析日志级别，不区分大小写
+// 支持别名：warning 等同于 warn，err 等同于 error
+func ParseLevel(level string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error", "err":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
 	default:
+		return InfoLevel, errors.Wrapf(ErrUnknownLevel, "%q", level)
+	}
+}
+
+// parseLogLevel 解析日志级别，无法识别时返回 info
+func parseLogLevel(level logconfig.LogLevel) Level {
+	l, err := ParseLevel(string(level))
+	if err != nil {
 		return InfoLevel
 	}
+	return l
 }
 
 // LogErrorWithStack 记录错误日志，包含错误详情和堆栈信息
@@ -60,11 +133,28 @@ func LogErrorWithStack(msg string, err error, keyvals ...any) {
 //   - err: 当前错误
 //   - keyvals: 额外的键值对信息，按照 key1, value1, key2, value2... 格式提供
 func LogErrorWithStackContext(ctx context.Context, msg string, err error, keyvals ...any) {
+	LogErrorLevelContext(ctx, ErrorLevel, msg, err, keyvals...)
+}
+
+// LogErrorLevel 以指定级别记录错误日志，包含错误详情和堆栈信息
+// 用于预期内的错误（如资源不存在），以 warn 或 info 级别记录可减少告警噪音
+// 参数:
+//   - level: 日志级别
+//   - msg: 错误消息
+//   - err: 当前错误
+//   - keyvals: 额外的键值对信息，按照 key1, value1, key2, value2... 格式提供
+func LogErrorLevel(level Level, msg string, err error, keyvals ...any) {
+	LogErrorLevelContext(context.Background(), level, msg, err, keyvals...)
+}
+
+// LogErrorLevelContext 使用上下文以指定级别记录错误日志，包含错误详情和堆栈信息
+// 是否附加堆栈由该级别的堆栈配置决定
+func LogErrorLevelContext(ctx context.Context, level Level, msg string, err error, keyvals ...any) {
 	// 构建日志字段
 	fields := make([]any, 0, len(keyvals)+4) // 预分配空间
 
 	// 检查是否需要显示堆栈
-	showStackTrace := ShouldShowStackTrace(ErrorLevel)
+	showStackTrace := ShouldShowStackTrace(level)
 
 	// 判断错误类型并处理
 	if err != nil {
@@ -84,29 +174,17 @@ func LogErrorWithStackContext(ctx context.Context, msg string, err error, keyval
 				// 如果需要显示堆栈，并且错误中没有包含堆栈信息，则尝试添加
 				if showStackTrace && !strings.Contains(errorChain, "[") {
 					// 尝试获取和添加堆栈信息
-					if formatter, ok := err.(fmt.Formatter); ok {
-						var buf bytes.Buffer
-						_, _ = fmt.Fprintf(&buf, "%+v", formatter)
-						fields = append(fields, "stack_trace", "\n"+buf.String())
+					if stack, ok := stackTraceValue(err); ok {
+						fields = append(fields, "stack_trace", stack)
 					}
 				}
 			}
 		} else {
 			// 非 AppError 类型，如果需要显示堆栈，则尝试添加
 			if showStackTrace {
-				// 检查是否是 pkg/errors 类型的错误
-				var pkgErr interface{ StackTrace() errors.StackTrace }
-				if errors.As(err, &pkgErr) {
-					var buf bytes.Buffer
-					_, _ = fmt.Fprintf(&buf, "%+v", pkgErr.StackTrace())
-					fields = append(fields, "stack_trace", "\n"+buf.String())
-				} else {
-					// 尝试获取和添加堆栈信息
-					if formatter, ok := err.(fmt.Formatter); ok {
-						var buf bytes.Buffer
-						_, _ = fmt.Fprintf(&buf, "%+v", formatter)
-						fields = append(fields, "stack_trace", "\n"+buf.String())
-					}
+				// pkg/errors 类型的错误解析为结构化堆栈，其他错误使用 %+v 格式化
+				if stack, ok := stackTraceValue(err); ok {
+					fields = append(fields, "stack_trace", stack)
 				}
 			}
 		}
@@ -115,6 +193,17 @@ func LogErrorWithStackContext(ctx context.Context, msg string, err error, keyval
 	// 添加额外的字段
 	fields = append(fields, keyvals...)
 
-	// 记录错误
-	ErrorContext(ctx, msg, fields...)
+	// 按级别记录
+	switch level {
+	case DebugLevel:
+		DebugContext(ctx, msg, fields...)
+	case InfoLevel:
+		InfoContext(ctx, msg, fields...)
+	case WarnLevel:
+		WarnContext(ctx, msg, fields...)
+	case FatalLevel:
+		FatalContext(ctx, msg, fields...)
+	default:
+		ErrorContext(ctx, msg, fields...)
+	}
 }
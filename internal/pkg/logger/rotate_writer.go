@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/limitcool/starter/pkg/logconfig"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 按时间轮转时文件名中的日期格式
+const (
+	dailyFileLayout  = "2006-01-02"
+	hourlyFileLayout = "2006-01-02T15"
+)
+
+// timeRotateWriter 按时间周期轮转的日志写入器
+// 每个周期写入一个带日期的新文件，例如 logs/app-2024-01-02.log，
+// 周期内仍由 lumberjack 按 MaxSize 进行大小轮转。
+// MaxAge、MaxBackups、Compress 同时作用于历史周期的文件。
+type timeRotateWriter struct {
+	mu        sync.Mutex
+	cleanMu   sync.Mutex // 串行化历史文件清理
+	config    logconfig.FileLogConfig
+	now       func() time.Time
+	current   *lumberjack.Logger
+	periodEnd time.Time
+}
+
+// newTimeRotateWriter 创建按时间轮转的日志写入器
+func newTimeRotateWriter(config logconfig.FileLogConfig) *timeRotateWriter {
+	return &timeRotateWriter{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// NewTimeRotateWriter 创建按 config.Rotation 周期轮转的日志写入器
+// now 为当前时间的来源，为 nil 时使用 time.Now，可用于测试跨周期的轮转和清理
+func NewTimeRotateWriter(config logconfig.FileLogConfig, now func() time.Time) io.WriteCloser {
+	w := newTimeRotateWriter(config)
+	if now != nil {
+		w.now = now
+	}
+	return w
+}
+
+// Write 实现 io.Writer 接口，跨越周期时自动切换到新文件
+func (w *timeRotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if now := w.now(); w.current == nil || !now.Before(w.periodEnd) {
+		w.rotate(now)
+	}
+
+	return w.current.Write(p)
+}
+
+// Sync 将缓冲写入磁盘，lumberjack 无缓冲，这里无需处理
+func (w *timeRotateWriter) Sync() error {
+	return nil
+}
+
+// Close 关闭当前日志文件
+func (w *timeRotateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.current == nil {
+		return nil
+	}
+
+	err := w.current.Close()
+	w.current = nil
+	return err
+}
+
+// rotate 切换到 now 所在周期的日志文件
+func (w *timeRotateWriter) rotate(now time.Time) {
+	if w.current != nil {
+		_ = w.current.Close()
+	}
+
+	start := w.config.PeriodStart(now)
+	w.periodEnd = start.Add(w.config.Rotation)
+
+	w.current = &lumberjack.Logger{
+		Filename:   w.datedFilename(start),
+		MaxSize:    w.config.MaxSize,
+		MaxAge:     w.config.MaxAge,
+		MaxBackups: w.config.MaxBackups,
+		Compress:   w.config.Compress,
+	}
+
+	// 后台清理历史周期的文件，不阻塞日志写入
+	go w.cleanup()
+}
+
+// datedFilename 生成带日期的文件名
+func (w *timeRotateWriter) datedFilename(start time.Time) string {
+	layout := dailyFileLayout
+	if w.config.Rotation%(24*time.Hour) != 0 {
+		layout = hourlyFileLayout
+	}
+
+	ext := filepath.Ext(w.config.Path)
+	base := strings.TrimSuffix(w.config.Path, ext)
+	return base + "-" + start.Format(layout) + ext
+}
+
+// cleanup 压缩并清理历史周期的日志文件
+func (w *timeRotateWriter) cleanup() {
+	w.cleanMu.Lock()
+	defer w.cleanMu.Unlock()
+
+	// 以清理时的当前文件为准，避免误删正在写入的文件
+	w.mu.Lock()
+	current := ""
+	if w.current != nil {
+		current = w.current.Filename
+	}
+	now := w.now()
+	w.mu.Unlock()
+
+	ext := filepath.Ext(w.config.Path)
+	// 当前周期内按大小轮转的备份由 lumberjack 自行管理
+	currentPrefix := strings.TrimSuffix(filepath.Base(current), ext) + "-"
+	dir := filepath.Dir(w.config.Path)
+	prefix := strings.TrimSuffix(filepath.Base(w.config.Path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+
+	var files []logFile
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+		if entry.IsDir() || path == current || !strings.HasPrefix(name, prefix) || strings.HasPrefix(name, currentPrefix) {
+			continue
+		}
+		if !strings.HasSuffix(name, ext) && !strings.HasSuffix(name, ext+".gz") {
+			continue
+		}
+
+		// 压缩上一周期遗留的未压缩文件
+		if w.config.Compress && !strings.HasSuffix(name, ".gz") {
+			if err := compressLogFile(path); err == nil {
+				path += ".gz"
+			}
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{path: path, modTime: info.ModTime()})
+	}
+
+	// 文件名中的日期可按字典序比较，倒序后保留最新的文件
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path > files[j].path
+	})
+
+	cutoff := now.AddDate(0, 0, -w.config.MaxAge)
+	for i, f := range files {
+		expired := w.config.MaxAge > 0 && f.modTime.Before(cutoff)
+		exceeded := w.config.MaxBackups > 0 && i >= w.config.MaxBackups
+		if expired || exceeded {
+			_ = os.Remove(f.path)
+		}
+	}
+}
+
+// compressLogFile 将日志文件压缩为 gzip 并删除原文件
+func compressLogFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	_ = src.Close()
+	return os.Remove(path)
+}
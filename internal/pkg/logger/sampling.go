@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSamplingKeys 采样计数器的最大数量，超出后清理已过期的计数器
+const maxSamplingKeys = 4096
+
+// SamplingOptions 采样选项
+// 每个周期内同一级别、同一消息的日志先记录 Initial 条，之后每 Thereafter 条记录 1 条
+type SamplingOptions struct {
+	Initial    int           // 每个周期内首先记录的条数
+	Thereafter int           // 超出后每多少条记录 1 条，小于等于 0 时丢弃剩余日志
+	Interval   time.Duration // 采样周期
+}
+
+// SamplingStats 采样统计
+type SamplingStats struct {
+	Logged     uint64 // 已记录的日志条数
+	Suppressed uint64 // 被采样丢弃的日志条数
+}
+
+// SamplingLogger 带采样功能的日志记录器
+// 包装任意 Logger，对高频重复日志降频，Fatal 级别日志不参与采样
+type SamplingLogger struct {
+	Logger
+	sampler *sampler
+}
+
+// sampler 采样状态，由同一 SamplingLogger 派生的记录器共享
+type sampler struct {
+	opts       SamplingOptions
+	mu         sync.Mutex
+	counters   map[samplingKey]*samplingCounter
+	logged     atomic.Uint64
+	suppressed atomic.Uint64
+}
+
+type samplingKey struct {
+	level Level
+	msg   string
+}
+
+type samplingCounter struct {
+	resetAt time.Time
+	count   int
+}
+
+// NewSamplingLogger 创建带采样功能的日志记录器
+func NewSamplingLogger(logger Logger, opts SamplingOptions) *SamplingLogger {
+	if opts.Initial <= 0 {
+		opts.Initial = 100
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+
+	return &SamplingLogger{
+		Logger: logger,
+		sampler: &sampler{
+			opts:     opts,
+			counters: make(map[samplingKey]*samplingCounter),
+		},
+	}
+}
+
+// Stats 获取采样统计
+func (l *SamplingLogger) Stats() SamplingStats {
+	return SamplingStats{
+		Logged:     l.sampler.logged.Load(),
+		Suppressed: l.sampler.suppressed.Load(),
+	}
+}
+
+// GetSamplingStats 获取默认日志记录器的采样统计
+// 默认日志记录器未启用采样时返回 false
+func GetSamplingStats() (SamplingStats, bool) {
+	if l, ok := Default().(*SamplingLogger); ok {
+		return l.Stats(), true
+	}
+	return SamplingStats{}, false
+}
+
+// allow 判断该条日志是否应被记录
+func (s *sampler) allow(level Level, msg string) bool {
+	now := time.Now()
+	key := samplingKey{level: level, msg: msg}
+
+	s.mu.Lock()
+	counter, ok := s.counters[key]
+	if !ok || !now.Before(counter.resetAt) {
+		if !ok && len(s.counters) >= maxSamplingKeys {
+			s.evictExpired(now)
+		}
+		counter = &samplingCounter{resetAt: now.Add(s.opts.Interval)}
+		s.counters[key] = counter
+	}
+	counter.count++
+	n := counter.count
+	s.mu.Unlock()
+
+	if n <= s.opts.Initial || (s.opts.Thereafter > 0 && (n-s.opts.Initial)%s.opts.Thereafter == 0) {
+		s.logged.Add(1)
+		return true
+	}
+
+	s.suppressed.Add(1)
+	return false
+}
+
+// evictExpired 清理已过期的计数器，调用方需持有锁
+func (s *sampler) evictExpired(now time.Time) {
+	for key, counter := range s.counters {
+		if !now.Before(counter.resetAt) {
+			delete(s.counters, key)
+		}
+	}
+}
+
+// Debug 实现 Logger 接口
+func (l *SamplingLogger) Debug(msg string, keysAndValues ...any) {
+	if l.sampler.allow(DebugLevel, msg) {
+		l.Logger.Debug(msg, keysAndValues...)
+	}
+}
+
+// Info 实现 Logger 接口
+func (l *SamplingLogger) Info(msg string, keysAndValues ...any) {
+	if l.sampler.allow(InfoLevel, msg) {
+		l.Logger.Info(msg, keysAndValues...)
+	}
+}
+
+// Warn 实现 Logger 接口
+func (l *SamplingLogger) Warn(msg string, keysAndValues ...any) {
+	if l.sampler.allow(WarnLevel, msg) {
+		l.Logger.Warn(msg, keysAndValues...)
+	}
+}
+
+// Error 实现 Logger 接口
+func (l *SamplingLogger) Error(msg string, keysAndValues ...any) {
+	if l.sampler.allow(ErrorLevel, msg) {
+		l.Logger.Error(msg, keysAndValues...)
+	}
+}
+
+// DebugContext 实现 Logger 接口
+func (l *SamplingLogger) DebugContext(ctx context.Context, msg string, keysAndValues ...any) {
+	if l.sampler.allow(DebugLevel, msg) {
+		l.Logger.DebugContext(ctx, msg, keysAndValues...)
+	}
+}
+
+// InfoContext 实现 Logger 接口
+func (l *SamplingLogger) InfoContext(ctx context.Context, msg string, keysAndValues ...any) {
+	if l.sampler.allow(InfoLevel, msg) {
+		l.Logger.InfoContext(ctx, msg, keysAndValues...)
+	}
+}
+
+// WarnContext 实现 Logger 接口
+func (l *SamplingLogger) WarnContext(ctx context.Context, msg string, keysAndValues ...any) {
+	if l.sampler.allow(WarnLevel, msg) {
+		l.Logger.WarnContext(ctx, msg, keysAndValues...)
+	}
+}
+
+// ErrorContext 实现 Logger 接口
+func (l *SamplingLogger) ErrorContext(ctx context.Context, msg string, keysAndValues ...any) {
+	if l.sampler.allow(ErrorLevel, msg) {
+		l.Logger.ErrorContext(ctx, msg, keysAndValues...)
+	}
+}
+
+// WithFields 实现 Logger 接口
+func (l *SamplingLogger) WithFields(fields map[string]any) Logger {
+	return &SamplingLogger{Logger: l.Logger.WithFields(fields), sampler: l.sampler}
+}
+
+// WithField 实现 Logger 接口
+func (l *SamplingLogger) WithField(key string, value any) Logger {
+	return &SamplingLogger{Logger: l.Logger.WithField(key, value), sampler: l.sampler}
+}
+
+// WithContext 实现 Logger 接口
+func (l *SamplingLogger) WithContext(ctx context.Context) Logger {
+	return &SamplingLogger{Logger: l.Logger.WithContext(ctx), sampler: l.sampler}
+}
+
+// Sync 实现 Sync 方法，转发给被包装的日志记录器
+func (l *SamplingLogger) Sync() error {
+	if s, ok := l.Logger.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// Close 实现 io.Closer 接口，转发给被包装的日志记录器
+func (l *SamplingLogger) Close() error {
+	if c, ok := l.Logger.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
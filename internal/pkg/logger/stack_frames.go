@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// StackFrame 堆栈帧
+type StackFrame struct {
+	Func string `json:"func"` // 函数名
+	File string `json:"file"` // 文件路径
+	Line int    `json:"line"` // 行号
+}
+
+// MarshalLogObject 实现 zapcore.ObjectMarshaler 接口
+func (f StackFrame) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("func", f.Func)
+	enc.AddString("file", f.File)
+	enc.AddInt("line", f.Line)
+	return nil
+}
+
+// StackFrames 结构化的堆栈
+// JSON 格式输出为帧对象数组，便于日志系统按帧检索；文本格式输出为多行字符串
+type StackFrames []StackFrame
+
+// MarshalLogArray 实现 zapcore.ArrayMarshaler 接口
+func (s StackFrames) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, frame := range s {
+		if err := enc.AppendObject(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String 格式化为与 %+v 相同的多行文本
+func (s StackFrames) String() string {
+	var b strings.Builder
+	for _, frame := range s {
+		b.WriteString("\n")
+		b.WriteString(frame.Func)
+		b.WriteString("\n\t")
+		b.WriteString(frame.File)
+		b.WriteString(":")
+		b.WriteString(strconv.Itoa(frame.Line))
+	}
+	return b.String()
+}
+
+// stackTracer pkg/errors 错误携带的堆栈
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// stackTraceValue 从错误中提取堆栈
+// 错误链中带有 pkg/errors 堆栈时解析为 StackFrames，否则回退为 %+v 格式化的文本
+func stackTraceValue(err error) (any, bool) {
+	var tracer stackTracer
+	if errors.As(err, &tracer) {
+		if frames := parseStackTrace(tracer.StackTrace()); len(frames) > 0 {
+			return frames, true
+		}
+	}
+
+	if formatter, ok := err.(fmt.Formatter); ok {
+		var buf bytes.Buffer
+		_, _ = fmt.Fprintf(&buf, "%+v", formatter)
+		return "\n" + buf.String(), true
+	}
+
+	return nil, false
+}
+
+// parseStackTrace 解析 pkg/errors 的堆栈，帧数受 MaxStackFrames 限制
+func parseStackTrace(st pkgerrors.StackTrace) StackFrames {
+	limit := GetStackTraceConfig().MaxStackFrames
+	if limit > 0 && len(st) > limit {
+		st = st[:limit]
+	}
+
+	frames := make(StackFrames, 0, len(st))
+	for _, f := range st {
+		// pkg/errors 的 Frame 为返回地址，减 1 得到调用指令所在位置
+		pc := uintptr(f) - 1
+		fn := runtime.FuncForPC(pc)
+		if fn == nil {
+			continue
+		}
+		file, line := fn.FileLine(pc)
+		frames = append(frames, StackFrame{Func: fn.Name(), File: file, Line: line})
+	}
+	return frames
+}
+
+// textStackCore 文本格式输出使用的 Core
+// 将 StackFrames 字段转换为多行文本，保持文本日志的可读性
+type textStackCore struct {
+	zapcore.Core
+}
+
+// With 实现 zapcore.Core 接口
+func (c *textStackCore) With(fields []zapcore.Field) zapcore.Core {
+	return &textStackCore{Core: c.Core.With(stringifyStackFields(fields))}
+}
+
+// Check 实现 zapcore.Core 接口
+func (c *textStackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口
+func (c *textStackCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, stringifyStackFields(fields))
+}
+
+// stringifyStackFields 将 StackFrames 字段替换为文本字段，不修改原切片
+func stringifyStackFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		frames, ok := f.Interface.(StackFrames)
+		if !ok || f.Type != zapcore.ArrayMarshalerType {
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields)
+		}
+		out[i] = zapcore.Field{Key: f.Key, Type: zapcore.StringType, String: frames.String()}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
@@ -35,6 +35,11 @@ func UpdateStackTraceConfig(enabled bool, level logconfig.LogLevel, maxFrames in
 		level = logconfig.LogLevelError
 	}
 
+	// 统一为标准写法，兼容大小写和别名
+	if l, err := ParseLevel(string(level)); err == nil {
+		level = logconfig.LogLevel(l.String())
+	}
+
 	stackTraceConfig = StackTraceConfig{
 		Enabled:        enabled,
 		Level:          level,
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/limitcool/starter/pkg/logconfig"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrSyslogUnsupported 当前平台不支持 syslog 输出
+var ErrSyslogUnsupported = errors.New("syslog output is not supported on this platform")
+
+// syslogWriter syslog 写入器，按优先级写入消息
+type syslogWriter interface {
+	Debug(msg string) error
+	Info(msg string) error
+	Warning(msg string) error
+	Err(msg string) error
+	Crit(msg string) error
+	Close() error
+}
+
+// syslogCore 写入 syslog 的 Core
+// 日志级别映射为 syslog 优先级：debug、info、warn、error 分别对应 debug、info、warning、err，
+// panic 和 fatal 对应 crit。时间由 syslog 服务记录，消息中不再包含时间字段。
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	writer  syslogWriter
+}
+
+// newSyslogCore 创建写入 syslog 的 Core，消息使用 JSON 格式
+func newSyslogCore(writer syslogWriter, level Level, config logconfig.LogConfig) zapcore.Core {
+	config.EncoderConfig.TimeKey = ""
+	return &syslogCore{
+		LevelEnabler: convertToZapLevel(level),
+		encoder:      newEncoder(JSONFormat, config, false),
+		writer:       writer,
+	}
+}
+
+// With 实现 zapcore.Core 接口
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, f := range fields {
+		f.AddTo(encoder)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, encoder: encoder, writer: c.writer}
+}
+
+// Check 实现 zapcore.Core 接口
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+
+	switch ent.Level {
+	case zapcore.DebugLevel:
+		return c.writer.Debug(msg)
+	case zapcore.InfoLevel:
+		return c.writer.Info(msg)
+	case zapcore.WarnLevel:
+		return c.writer.Warning(msg)
+	case zapcore.ErrorLevel:
+		return c.writer.Err(msg)
+	default:
+		return c.writer.Crit(msg)
+	}
+}
+
+// Sync 实现 zapcore.Core 接口，syslog 写入无缓冲
+func (c *syslogCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package logger
+
+import "github.com/limitcool/starter/pkg/logconfig"
+
+// dialSyslog 当前平台不支持 syslog，返回 ErrSyslogUnsupported
+func dialSyslog(logconfig.SyslogConfig) (syslogWriter, error) {
+	return nil, ErrSyslogUnsupported
+}
@@ -0,0 +1,35 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+
+	"github.com/limitcool/starter/pkg/logconfig"
+)
+
+// syslogFacilities 支持的 syslog 设施
+var syslogFacilities = map[string]syslog.Priority{
+	"":       syslog.LOG_USER,
+	"user":   syslog.LOG_USER,
+	"daemon": syslog.LOG_DAEMON,
+	"local0": syslog.LOG_LOCAL0,
+	"local1": syslog.LOG_LOCAL1,
+	"local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4,
+	"local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6,
+	"local7": syslog.LOG_LOCAL7,
+}
+
+// dialSyslog 连接 syslog 服务
+func dialSyslog(config logconfig.SyslogConfig) (syslogWriter, error) {
+	facility, ok := syslogFacilities[strings.ToLower(config.Facility)]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %q", config.Facility)
+	}
+	return syslog.Dial(config.Network, config.Address, facility|syslog.LOG_INFO, config.Tag)
+}
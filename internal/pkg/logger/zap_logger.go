@@ -2,11 +2,14 @@ package logger
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/limitcool/starter/pkg/logconfig"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -22,6 +25,7 @@ type ZapLogger struct {
 	development   bool
 	sampling      bool
 	encoderConfig logconfig.EncoderConfig
+	closer        *outputCloser // 日志文件输出，派生的记录器共享
 }
 
 // NewZapLogger 创建一个新的 ZapLogger
@@ -49,11 +53,14 @@ func NewZapLoggerWithConfig(config logconfig.LogConfig) *ZapLogger {
 	// 检查是否需要输出到控制台
 	hasConsole := false
 	hasFile := false
+	hasSyslog := false
 	for _, output := range config.Output {
 		if output == "console" {
 			hasConsole = true
 		} else if output == "file" {
 			hasFile = true
+		} else if output == "syslog" {
+			hasSyslog = true
 		}
 	}
 
@@ -62,31 +69,53 @@ func NewZapLoggerWithConfig(config logconfig.LogConfig) *ZapLogger {
 		hasConsole = true
 	}
 
+	// 输出以 debug 级别创建，生效的级别由外层的 levelCore 决定，便于按请求调整级别
 	// 添加控制台输出 - 使用text格式
 	if hasConsole {
-		consoleCore := createCore(os.Stdout, level, TextFormat, config)
+		consoleCore := createCore(os.Stdout, DebugLevel, TextFormat, config)
 		cores = append(cores, consoleCore)
 	}
 
 	// 添加文件输出 - 使用JSON格式
+	closer := &outputCloser{}
 	if hasFile {
-		fileOutput := &lumberjack.Logger{
-			Filename:   config.FileConfig.Path,
-			MaxSize:    config.FileConfig.MaxSize,
-			MaxAge:     config.FileConfig.MaxAge,
-			MaxBackups: config.FileConfig.MaxBackups,
-			Compress:   config.FileConfig.Compress,
+		fileWriter := newFileWriter(config.FileConfig)
+		if c, ok := fileWriter.(io.Closer); ok {
+			closer.closers = append(closer.closers, c)
 		}
-		fileCore := createCore(fileOutput, level, JSONFormat, config)
+		fileCore := createCore(fileWriter, DebugLevel, JSONFormat, config)
 		cores = append(cores, fileCore)
 	}
 
+	// 添加 syslog 输出 - 使用JSON格式，按日志级别映射 syslog 优先级
+	// 平台不支持或连接失败时输出提示并忽略，不影响其他输出
+	if hasSyslog {
+		writer, err := dialSyslog(config.Syslog)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logger: syslog output disabled: %v\n", err)
+		} else {
+			closer.closers = append(closer.closers, writer)
+			cores = append(cores, newSyslogCore(writer, DebugLevel, config))
+		}
+	}
+
 	// 如果没有输出，默认输出到控制台
 	if len(cores) == 0 {
-		consoleCore := createCore(os.Stdout, level, TextFormat, config)
+		consoleCore := createCore(os.Stdout, DebugLevel, TextFormat, config)
 		cores = append(cores, consoleCore)
 	}
 
+	// 只在指定级别及以上记录调用位置
+	if config.ReportCaller {
+		callerLevel := DebugLevel
+		if config.CallerLevel != "" {
+			callerLevel = parseLogLevel(config.CallerLevel)
+		}
+		for i, c := range cores {
+			cores[i] = &callerLevelCore{Core: c, level: convertToZapLevel(callerLevel)}
+		}
+	}
+
 	// 合并所有core
 	var core zapcore.Core
 	if len(cores) == 1 {
@@ -94,6 +123,7 @@ func NewZapLoggerWithConfig(config logconfig.LogConfig) *ZapLogger {
 	} else {
 		core = zapcore.NewTee(cores...)
 	}
+	core = &levelCore{Core: core, level: convertToZapLevel(level)}
 
 	// 创建 Logger 选项
 	options := []zap.Option{
@@ -104,15 +134,27 @@ func NewZapLoggerWithConfig(config logconfig.LogConfig) *ZapLogger {
 	// 添加堆栈跟踪
 	options = append(options, zap.AddStacktrace(stackLevel))
 
+	// Fatal 日志写入后先关闭日志文件再退出，保证最后的错误落盘
+	options = append(options, zap.WithFatalHook(closer))
+
 	// 开发模式设置
 	if config.Development {
 		options = append(options, zap.Development())
 	}
 
-	// 创建 Logger
+	// 每条日志附带服务名，便于多服务日志聚合时区分来源
+	if config.ServiceName != "" {
+		options = append(options, zap.Fields(zap.String(ServiceKey, config.ServiceName)))
+	}
+
+	// 创建 Logger，前缀作为记录器名称输出
 	structLogger := zap.New(core, options...)
+	if prefix := config.LogPrefix(); prefix != "" {
+		structLogger = structLogger.Named(prefix)
+	}
 
 	return &ZapLogger{
+		closer:        closer,
 		logger:        structLogger.Sugar(),
 		structLogger:  structLogger,
 		level:         level,
@@ -124,8 +166,121 @@ func NewZapLoggerWithConfig(config logconfig.LogConfig) *ZapLogger {
 	}
 }
 
+// outputCloser 关闭日志的文件输出，多次调用只关闭一次
+type outputCloser struct {
+	once    sync.Once
+	closers []io.Closer
+	err     error
+}
+
+// Close 关闭所有文件和 syslog 输出
+func (c *outputCloser) Close() error {
+	c.once.Do(func() {
+		for _, closer := range c.closers {
+			if err := closer.Close(); err != nil && c.err == nil {
+				c.err = err
+			}
+		}
+	})
+	return c.err
+}
+
+// OnWrite 实现 zapcore.CheckWriteHook 接口，Fatal 日志写入后关闭文件输出并退出
+func (c *outputCloser) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	_ = c.Close()
+	os.Exit(1)
+}
+
+// Sync 将缓冲的日志写入输出
+func (l *ZapLogger) Sync() error {
+	return l.structLogger.Sync()
+}
+
+// Close 刷新缓冲并关闭日志文件，关闭后写入文件的日志会重新打开文件
+func (l *ZapLogger) Close() error {
+	_ = l.Sync() // 标准输出不支持 Sync，忽略错误
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// callerLevelCore 按级别过滤调用位置的 Core
+// 低于 level 的日志去掉调用位置，如只在 warn 及以上的日志中输出 file:line
+type callerLevelCore struct {
+	zapcore.Core
+	level zapcore.Level
+}
+
+// With 实现 zapcore.Core 接口
+func (c *callerLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &callerLevelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+// Check 实现 zapcore.Core 接口
+func (c *callerLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 实现 zapcore.Core 接口
+func (c *callerLevelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level < c.level {
+		ent.Caller = zapcore.EntryCaller{}
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// newFileWriter 创建文件日志写入器
+// 配置了 Rotation 时按时间轮转，否则仅按大小轮转
+func newFileWriter(config logconfig.FileLogConfig) io.Writer {
+	if config.Rotation > 0 {
+		return newTimeRotateWriter(config)
+	}
+
+	return &lumberjack.Logger{
+		Filename:   config.Path,
+		MaxSize:    config.MaxSize,
+		MaxAge:     config.MaxAge,
+		MaxBackups: config.MaxBackups,
+		Compress:   config.Compress,
+	}
+}
+
 // createCore 创建一个zapcore.Core
 func createCore(w io.Writer, level Level, format Format, config logconfig.LogConfig) zapcore.Core {
+	// 创建 Core
+	core := zapcore.NewCore(
+		newEncoder(format, config, colorEnabled(w)),
+		zapcore.AddSync(w),
+		convertToZapLevel(level),
+	)
+
+	// 文本格式下结构化堆栈输出为多行文本
+	if format != JSONFormat {
+		return &textStackCore{Core: core}
+	}
+	return core
+}
+
+// colorEnabled 判断写入器是否可以输出颜色
+// 设置了 NO_COLOR 环境变量（https://no-color.org）或写入器不是终端时不输出颜色
+func colorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// newEncoder 根据格式和编码器配置创建编码器，color 为 false 时不输出颜色
+func newEncoder(format Format, config logconfig.LogConfig, color bool) zapcore.Encoder {
 	// 创建编码器配置
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:          config.EncoderConfig.TimeKey,
@@ -135,7 +290,7 @@ func createCore(w io.Writer, level Level, format Format, config logconfig.LogCon
 		MessageKey:       config.EncoderConfig.MessageKey,
 		StacktraceKey:    config.EncoderConfig.StacktraceKey,
 		LineEnding:       zapcore.DefaultLineEnding,
-		EncodeLevel:      getZapLevelEncoder(config.EncoderConfig.EncodeLevel),
+		EncodeLevel:      getZapLevelEncoder(config.EncoderConfig.EncodeLevel, color && format != JSONFormat),
 		EncodeTime:       getZapTimeEncoder(config.EncoderConfig.EncodeTime),
 		EncodeDuration:   getZapDurationEncoder(config.EncoderConfig.EncodeDuration),
 		EncodeCaller:     getZapCallerEncoder(config.EncoderConfig.EncodeCaller),
@@ -143,19 +298,10 @@ func createCore(w io.Writer, level Level, format Format, config logconfig.LogCon
 	}
 
 	// 创建编码器
-	var encoder zapcore.Encoder
 	if format == JSONFormat {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
-	} else {
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+		return zapcore.NewJSONEncoder(encoderConfig)
 	}
-
-	// 创建 Core
-	return zapcore.NewCore(
-		encoder,
-		zapcore.AddSync(w),
-		convertToZapLevel(level),
-	)
+	return zapcore.NewConsoleEncoder(encoderConfig)
 }
 
 // newZapLogger 创建一个新的 ZapLogger
@@ -170,7 +316,7 @@ func newZapLogger(w io.Writer, level Level, format Format, stackLevel *zapcore.L
 	}
 
 	// 创建core
-	core := createCore(w, level, format, config)
+	core := &levelCore{Core: createCore(w, DebugLevel, format, config), level: convertToZapLevel(level)}
 
 	// 创建 Logger 选项
 	options := []zap.Option{
@@ -257,6 +403,7 @@ func (l *ZapLogger) WithFields(fields map[string]any) Logger {
 		development:   l.development,
 		sampling:      l.sampling,
 		encoderConfig: l.encoderConfig,
+		closer:        l.closer,
 	}
 }
 
@@ -273,6 +420,7 @@ func (l *ZapLogger) WithField(key string, value any) Logger {
 		development:   l.development,
 		sampling:      l.sampling,
 		encoderConfig: l.encoderConfig,
+		closer:        l.closer,
 	}
 }
 
@@ -318,7 +466,8 @@ func (l *ZapLogger) WithContext(ctx context.Context) Logger {
 		zapFields = append(zapFields, zap.Any(k, v))
 	}
 
-	return &ZapLogger{
+	// 上下文中设置了日志级别时使用该级别
+	return applyContextLevel(ctx, &ZapLogger{
 		logger:        newLogger,
 		structLogger:  l.structLogger.With(zapFields...),
 		level:         l.level,
@@ -327,7 +476,16 @@ func (l *ZapLogger) WithContext(ctx context.Context) Logger {
 		development:   l.development,
 		sampling:      l.sampling,
 		encoderConfig: l.encoderConfig,
+		closer:        l.closer,
+	})
+}
+
+// contextStructLogger 获取结构化日志器，上下文中设置了日志级别时使用该级别
+func (l *ZapLogger) contextStructLogger(ctx context.Context) *zap.Logger {
+	if level, ok := LevelFromContext(ctx); ok {
+		return l.structLogger.WithOptions(withZapLevel(level))
 	}
+	return l.structLogger
 }
 
 // DebugContext 实现 Logger 接口
@@ -353,7 +511,7 @@ func (l *ZapLogger) DebugContext(ctx context.Context, msg string, keysAndValues
 			}
 		}
 
-		l.structLogger.Debug(msg, zapFields...)
+		l.contextStructLogger(ctx).Debug(msg, zapFields...)
 	} else {
 		// 使用非结构化日志
 		l.WithContext(ctx).Debug(msg, keysAndValues...)
@@ -383,7 +541,7 @@ func (l *ZapLogger) InfoContext(ctx context.Context, msg string, keysAndValues .
 			}
 		}
 
-		l.structLogger.Info(msg, zapFields...)
+		l.contextStructLogger(ctx).Info(msg, zapFields...)
 	} else {
 		// 使用非结构化日志
 		l.WithContext(ctx).Info(msg, keysAndValues...)
@@ -413,7 +571,7 @@ func (l *ZapLogger) WarnContext(ctx context.Context, msg string, keysAndValues .
 			}
 		}
 
-		l.structLogger.Warn(msg, zapFields...)
+		l.contextStructLogger(ctx).Warn(msg, zapFields...)
 	} else {
 		// 使用非结构化日志
 		l.WithContext(ctx).Warn(msg, keysAndValues...)
@@ -443,7 +601,7 @@ func (l *ZapLogger) ErrorContext(ctx context.Context, msg string, keysAndValues
 			}
 		}
 
-		l.structLogger.Error(msg, zapFields...)
+		l.contextStructLogger(ctx).Error(msg, zapFields...)
 	} else {
 		// 使用非结构化日志
 		l.WithContext(ctx).Error(msg, keysAndValues...)
@@ -473,7 +631,7 @@ func (l *ZapLogger) FatalContext(ctx context.Context, msg string, keysAndValues
 			}
 		}
 
-		l.structLogger.Fatal(msg, zapFields...)
+		l.contextStructLogger(ctx).Fatal(msg, zapFields...)
 	} else {
 		// 使用非结构化日志
 		l.WithContext(ctx).Fatal(msg, keysAndValues...)
@@ -548,6 +706,12 @@ func extractContextFields(ctx context.Context) map[string]any {
 		fields["span_id"] = spanID
 	}
 
+	// 存在有效的 OpenTelemetry Span 时，优先使用其追踪信息
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		fields["trace_id"] = spanCtx.TraceID().String()
+		fields["span_id"] = spanCtx.SpanID().String()
+	}
+
 	return fields
 }
 
@@ -570,11 +734,15 @@ func convertToZapLevel(level Level) zapcore.Level {
 }
 
 // getZapLevelEncoder 获取级别编码器
-func getZapLevelEncoder(encoderType string) zapcore.LevelEncoder {
+// color 编码方式只在 color 为 true 时输出颜色，否则退化为 capital，避免 ANSI 转义码写入文件
+func getZapLevelEncoder(encoderType string, color bool) zapcore.LevelEncoder {
 	switch encoderType {
 	case "capital":
 		return zapcore.CapitalLevelEncoder
 	case "color":
+		if !color {
+			return zapcore.CapitalLevelEncoder
+		}
 		return zapcore.CapitalColorLevelEncoder
 	case "lowercase":
 		return zapcore.LowercaseLevelEncoder
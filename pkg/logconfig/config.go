@@ -35,17 +35,25 @@ const (
 
 // LogConfig 日志配置
 type LogConfig struct {
-	Level             LogLevel      `yaml:"level" json:"level"`                             // 日志级别
-	Format            LogFormat     `yaml:"format" json:"format"`                           // 日志格式
-	Style             LogStyle      `yaml:"style" json:"style"`                             // 日志风格（结构化或非结构化）
-	Output            []string      `yaml:"output" json:"output"`                           // 日志输出位置
-	FileConfig        FileLogConfig `yaml:"file_config" json:"file_config"`                 // 文件日志配置
-	StackTraceLevel   LogLevel      `yaml:"stack_trace_level" json:"stack_trace_level"`     // 堆栈跟踪级别
-	StackTraceEnabled bool          `yaml:"stack_trace_enabled" json:"stack_trace_enabled"` // 是否启用堆栈跟踪
-	MaxStackFrames    int           `yaml:"max_stack_frames" json:"max_stack_frames"`       // 最大堆栈帧数
-	Sampling          bool          `yaml:"sampling" json:"sampling"`                       // 是否启用采样（高频日志降频）
-	Development       bool          `yaml:"development" json:"development"`                 // 是否为开发模式（更详细的日志）
-	EncoderConfig     EncoderConfig `yaml:"encoder_config" json:"encoder_config"`           // 编码器配置
+	Level              LogLevel      `yaml:"level" json:"level"`                             // 日志级别
+	Prefix             string        `yaml:"prefix" json:"prefix"`                           // 日志前缀，为空时使用 ServiceName
+	ServiceName        string        `yaml:"service_name" json:"service_name"`               // 服务名，作为 service 字段写入每条日志，便于多服务日志聚合
+	Format             LogFormat     `yaml:"format" json:"format"`                           // 日志格式
+	Style              LogStyle      `yaml:"style" json:"style"`                             // 日志风格（结构化或非结构化）
+	Output             []string      `yaml:"output" json:"output"`                           // 日志输出位置
+	FileConfig         FileLogConfig `yaml:"file_config" json:"file_config"`                 // 文件日志配置
+	Syslog             SyslogConfig  `yaml:"syslog" json:"syslog"`                           // syslog 日志配置，Output 包含 syslog 时生效
+	StackTraceLevel    LogLevel      `yaml:"stack_trace_level" json:"stack_trace_level"`     // 堆栈跟踪级别
+	StackTraceEnabled  bool          `yaml:"stack_trace_enabled" json:"stack_trace_enabled"` // 是否启用堆栈跟踪
+	MaxStackFrames     int           `yaml:"max_stack_frames" json:"max_stack_frames"`       // 最大堆栈帧数
+	Sampling           bool          `yaml:"sampling" json:"sampling"`                       // 是否启用采样（高频日志降频）
+	SamplingInitial    int           `yaml:"sampling_initial" json:"sampling_initial"`       // 采样周期内同一日志首先记录的条数
+	SamplingThereafter int           `yaml:"sampling_thereafter" json:"sampling_thereafter"` // 超出后每多少条记录 1 条
+	SamplingInterval   time.Duration `yaml:"sampling_interval" json:"sampling_interval"`     // 采样周期
+	ReportCaller       bool          `yaml:"report_caller" json:"report_caller"`             // 是否只在不低于 CallerLevel 的日志中记录调用位置，false 时所有级别都记录
+	CallerLevel        LogLevel      `yaml:"caller_level" json:"caller_level"`               // 记录调用位置的最低日志级别，默认 debug
+	Development        bool          `yaml:"development" json:"development"`                 // 是否为开发模式（更详细的日志）
+	EncoderConfig      EncoderConfig `yaml:"encoder_config" json:"encoder_config"`           // 编码器配置
 }
 
 // FileLogConfig 文件日志配置
@@ -55,7 +63,35 @@ type FileLogConfig struct {
 	MaxAge     int           `yaml:"max_age" json:"max_age"`         // 日志文件保留天数
 	MaxBackups int           `yaml:"max_backups" json:"max_backups"` // 最大备份数
 	Compress   bool          `yaml:"compress" json:"compress"`       // 是否压缩
-	Rotation   time.Duration `yaml:"rotation" json:"rotation"`       // 日志轮转时间间隔
+	Rotation   time.Duration `yaml:"rotation" json:"rotation"`       // 日志轮转时间间隔，如 24h 按天、1h 按小时，0 表示仅按大小轮转
+}
+
+// PeriodStart 计算 now 所在轮转周期的起始时间
+// 按天及以上的周期以本地零点对齐，并从 1970-01-01 起按天数划分，周期不会在跨年时提前重置；
+// 其余周期按时长截断
+func (c FileLogConfig) PeriodStart(now time.Time) time.Time {
+	if c.Rotation%(24*time.Hour) == 0 {
+		days := int64(c.Rotation / (24 * time.Hour))
+		year, month, day := now.Date()
+		midnight := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+		// 按日历日计算天数，不受时区和夏令时影响
+		epochDays := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400
+		offset := epochDays % days
+		if offset < 0 {
+			offset += days
+		}
+		return midnight.AddDate(0, 0, -int(offset))
+	}
+	return now.Truncate(c.Rotation)
+}
+
+// SyslogConfig syslog 日志配置
+// Network 和 Address 为空时连接本机 syslog 服务（systemd 环境下由 journald 接收）
+type SyslogConfig struct {
+	Network  string `yaml:"network" json:"network"`   // 网络类型，如 udp、tcp，为空时使用本机 unix socket
+	Address  string `yaml:"address" json:"address"`   // syslog 服务地址，如 127.0.0.1:514
+	Tag      string `yaml:"tag" json:"tag"`           // 日志标签，为空时使用程序名
+	Facility string `yaml:"facility" json:"facility"` // 设施，如 user、daemon、local0~local7，默认 user
 }
 
 // EncoderConfig 编码器配置
@@ -88,6 +124,14 @@ func DefaultEncoderConfig() EncoderConfig {
 	}
 }
 
+// LogPrefix 返回日志前缀，未配置 Prefix 时使用 ServiceName
+func (c LogConfig) LogPrefix() string {
+	if c.Prefix != "" {
+		return c.Prefix
+	}
+	return c.ServiceName
+}
+
 // DefaultLogConfig 返回默认日志配置
 func DefaultLogConfig() LogConfig {
 	return LogConfig{
@@ -102,11 +146,14 @@ func DefaultLogConfig() LogConfig {
 			MaxBackups: 10,
 			Compress:   false,
 		},
-		StackTraceEnabled: true,
-		StackTraceLevel:   LogLevelError,
-		MaxStackFrames:    64,
-		Sampling:          false,
-		Development:       false,
-		EncoderConfig:     DefaultEncoderConfig(),
+		StackTraceEnabled:  true,
+		StackTraceLevel:    LogLevelError,
+		MaxStackFrames:     64,
+		Sampling:           false,
+		SamplingInitial:    100,
+		SamplingThereafter: 100,
+		SamplingInterval:   time.Second,
+		Development:        false,
+		EncoderConfig:      DefaultEncoderConfig(),
 	}
 }
@@ -0,0 +1,966 @@
+package response_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/api/response"
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/model"
+	"github.com/limitcool/starter/internal/pkg/logger"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type user struct {
+	ID       int64
+	Username string
+	Password string
+}
+
+type userDTO struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+func TestMapPage(t *testing.T) {
+	users := []user{
+		{ID: 1, Username: "alice", Password: "secret"},
+		{ID: 2, Username: "bob", Password: "secret"},
+	}
+	page := response.NewPageResult(users, 42, 3, 2)
+
+	result := response.MapPage(page, func(u user) userDTO {
+		return userDTO{ID: u.ID, Username: u.Username}
+	})
+
+	assert.Equal(t, int64(42), result.Total)
+	assert.Equal(t, 3, result.Page)
+	assert.Equal(t, 2, result.PageSize)
+	assert.Equal(t, []userDTO{
+		{ID: 1, Username: "alice"},
+		{ID: 2, Username: "bob"},
+	}, result.List)
+
+	// 空列表应转换为空切片而不是 nil
+	empty := response.MapPage(response.NewPageResult([]user(nil), 0, 1, 10), func(u user) userDTO {
+		return userDTO{ID: u.ID}
+	})
+	assert.NotNil(t, empty.List)
+	assert.Empty(t, empty.List)
+}
+
+func TestMapSlice(t *testing.T) {
+	dtos := response.MapSlice([]user{{ID: 1, Username: "alice", Password: "secret"}}, func(u user) userDTO {
+		return userDTO{ID: u.ID, Username: u.Username}
+	})
+	assert.Equal(t, []userDTO{{ID: 1, Username: "alice"}}, dtos)
+
+	assert.NotNil(t, response.MapSlice([]user(nil), func(u user) userDTO { return userDTO{} }))
+}
+
+func TestBinary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	data := []byte("%PDF-1.4 fake pdf")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/report", nil)
+
+	response.Binary(c, "application/pdf", data, response.WithAttachment("报表.pdf"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, strconv.Itoa(len(data)), w.Header().Get("Content-Length"))
+	assert.Equal(t, data, w.Body.Bytes())
+
+	_, params, err := mime.ParseMediaType(w.Header().Get("Content-Disposition"))
+	assert.NoError(t, err)
+	assert.Equal(t, "报表.pdf", params["filename"])
+}
+
+func TestErrorValidationDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type signupRequest struct {
+		Email    string `binding:"required,email"`
+		UserName string `binding:"required,min=3"`
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(`{"UserName":"ab"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	var req signupRequest
+	err := c.ShouldBindJSON(&req)
+	assert.Error(t, err)
+
+	response.Error(c, err)
+
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, errspec.ErrInvalidParams.Code(), result.Code)
+	assert.Equal(t, map[string]any{
+		"email":     "email is required",
+		"user_name": "user_name must be at least 3 characters long",
+	}, result.Details)
+}
+
+func TestMultiStatusCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	failure := errspec.ErrRecordNotExist.New(context.Background())
+
+	testCases := []struct {
+		name       string
+		items      []response.BatchItem[int]
+		wantCode   int
+		wantStatus int
+	}{
+		{
+			name: "All success",
+			items: []response.BatchItem[int]{
+				response.BatchSuccess(1, 10),
+				response.BatchSuccess(2, 20),
+			},
+			wantCode:   errspec.Success.Code(),
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Mixed",
+			items: []response.BatchItem[int]{
+				response.BatchSuccess(1, 10),
+				response.BatchFailure[int](2, failure),
+			},
+			wantCode:   errspec.ErrBatchPartialSuccess.Code(),
+			wantStatus: http.StatusMultiStatus,
+		},
+		{
+			name: "All failed",
+			items: []response.BatchItem[int]{
+				response.BatchFailure[int](1, failure),
+				response.BatchFailure[int](2, failure),
+			},
+			wantCode:   errspec.ErrBatchFailed.Code(),
+			wantStatus: http.StatusMultiStatus,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/batch", nil)
+
+			response.MultiStatus(c, tc.items)
+
+			var result response.Result[response.BatchResult[int]]
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, tc.wantCode, result.Code)
+			assert.Equal(t, len(tc.items), result.Data.Total)
+			assert.Equal(t, len(tc.items), result.Data.Succeeded+result.Data.Failed)
+		})
+	}
+}
+
+func TestNewPageResultMetadata(t *testing.T) {
+	testCases := []struct {
+		name           string
+		total          int64
+		page, pageSize int
+		wantPages      int
+		wantNext       bool
+		wantPrev       bool
+	}{
+		{name: "First page", total: 25, page: 1, pageSize: 10, wantPages: 3, wantNext: true},
+		{name: "Middle page", total: 25, page: 2, pageSize: 10, wantPages: 3, wantNext: true, wantPrev: true},
+		{name: "Last page", total: 25, page: 3, pageSize: 10, wantPages: 3, wantPrev: true},
+		{name: "Empty result", total: 0, page: 1, pageSize: 10, wantPages: 0},
+		{name: "Invalid page size", total: 25, page: 1, pageSize: 0, wantPages: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := response.NewPageResult([]int{}, tc.total, tc.page, tc.pageSize)
+			assert.Equal(t, tc.wantPages, p.TotalPages)
+			assert.Equal(t, tc.wantNext, p.HasNext)
+			assert.Equal(t, tc.wantPrev, p.HasPrev)
+		})
+	}
+}
+
+func TestErrorDebugInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newError := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		response.Error(c, errspec.ErrInternal.New(context.Background()).Wrap(pkgerrors.New("connection refused")))
+		return w
+	}
+
+	// 默认不返回调试信息
+	assert.NotContains(t, newError().Body.String(), `"debug"`)
+
+	response.Configure(response.WithDebug(true))
+	defer response.Configure(response.WithDebug(false))
+
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(newError().Body.Bytes(), &result))
+	if assert.NotNil(t, result.Debug) {
+		assert.Contains(t, result.Debug.ErrorChain, "connection refused")
+		assert.Contains(t, result.Debug.Stack, "TestErrorDebugInfo")
+	}
+}
+
+func TestErrorValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/orders", nil)
+
+	response.Error(c, errspec.NewValidationError(c.Request.Context(), map[string]string{
+		"quantity": "quantity exceeds stock",
+	}))
+
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, errspec.ErrValidation.Code(), result.Code)
+	assert.Equal(t, map[string]any{"quantity": "quantity exceeds stock"}, result.Details)
+}
+
+func TestFieldNamingCamelCase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	response.Configure(response.WithFieldNaming(response.CamelCase))
+	defer response.Configure(response.WithFieldNaming(response.SnakeCase))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Set("request_id", "req-1")
+
+	type item struct {
+		CreatedAt int64  `json:"created_at"`
+		Private   string `json:"_private_flag"`
+	}
+	response.Page(c, []item{{CreatedAt: 1700000000123, Private: "x"}}, 1, 1, 10)
+
+	body := w.Body.String()
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	for _, key := range []string{`"requestId"`, `"pageSize"`, `"totalPages"`, `"hasNext"`, `"createdAt":1700000000123`, `"_privateFlag"`} {
+		assert.Contains(t, body, key)
+	}
+	assert.NotContains(t, body, "page_size")
+}
+
+func TestErrorChainLogging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+	logged := func(err error) string {
+		buf.Reset()
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		response.Error(c, err)
+		return buf.String()
+	}
+
+	ctx := context.Background()
+
+	// 默认所有错误都记录错误链
+	assert.Contains(t, logged(errspec.ErrNotFound.New(ctx)), "error_chain")
+
+	response.Configure(
+		response.WithErrorChainMinStatus(http.StatusInternalServerError),
+		response.WithoutErrorChainCodes(errspec.ErrDatabaseUnavailable.Code()),
+	)
+	defer response.Configure(response.WithErrorChainMinStatus(0), response.WithoutErrorChainCodes())
+
+	out := logged(errspec.ErrNotFound.New(ctx))
+	assert.NotContains(t, out, "error_chain")
+	assert.Contains(t, out, `"code":1004`)
+	assert.NotContains(t, logged(errspec.ErrDatabaseUnavailable.New(ctx)), "error_chain")
+	assert.Contains(t, logged(errspec.ErrInternal.New(ctx)), "error_chain")
+}
+
+func TestTimeFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer response.Configure(response.WithTimeFormat(response.UnixSeconds))
+
+	timestamp := func(format response.TimeFormat) json.RawMessage {
+		response.Configure(response.WithTimeFormat(format))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		response.SuccessNoData(c)
+
+		var body struct {
+			Timestamp json.RawMessage `json:"timestamp"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body.Timestamp
+	}
+
+	assert.Len(t, string(timestamp(response.UnixSeconds)), 10)
+	assert.Len(t, string(timestamp(response.UnixMillis)), 13)
+
+	var s string
+	assert.NoError(t, json.Unmarshal(timestamp(response.RFC3339), &s))
+	_, err := time.Parse(time.RFC3339, s)
+	assert.NoError(t, err)
+
+	// 反序列化兼容各种格式，Time 字段始终为秒级时间戳
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal([]byte(`{"code":0,"timestamp":1700000000123}`), &result))
+	assert.Equal(t, int64(1700000000), result.Time)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"timestamp":"2023-11-14T22:13:20.123Z"}`), &result))
+	assert.Equal(t, int64(1700000000), result.Time)
+
+	// 重新序列化时保留毫秒精度
+	response.Configure(response.WithTimeFormat(response.UnixMillis))
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"timestamp":1700000000123`)
+	response.Configure(response.WithTimeFormat(response.UnixSeconds))
+
+	// 未设置时间时不输出 timestamp 字段
+	data, err = json.Marshal(response.Result[struct{}]{})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "timestamp")
+}
+
+func TestErrorWrappedCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// 顶层错误没有错误码时使用错误链中的业务错误码
+	err := fmt.Errorf("load order: %w", errspec.ErrRecordNotExist.New(context.Background()))
+	response.Error(c, err)
+
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, errspec.ErrRecordNotExist.Code(), result.Code)
+
+	// 多层业务错误时使用最近的错误码，处理器有意转换的错误码不被内层覆盖
+	ctx := context.Background()
+	translated := errspec.ErrInternal.New(ctx).Wrap(errspec.ErrRecordNotExist.New(ctx))
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	response.Error(c, fmt.Errorf("handle order: %w", translated))
+
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, errspec.ErrInternal.Code(), result.Code)
+	assert.Equal(t, translated.Error(), result.Message)
+}
+
+func TestCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	response.Configure(response.WithCompression(true), response.WithCompressionMinSize(256))
+	defer response.Configure(response.WithCompression(false), response.WithCompressionMinSize(0))
+
+	send := func(acceptEncoding string, data any) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept-Encoding", acceptEncoding)
+		response.Success(c, data)
+		return w
+	}
+
+	large := strings.Repeat("payload ", 50)
+
+	// 客户端支持 gzip 时压缩大响应
+	w := send("deflate, gzip", large)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	reader, err := gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), large)
+
+	// 只支持 deflate 时使用 deflate
+	assert.Equal(t, "deflate", send("gzip;q=0, deflate", large).Header().Get("Content-Encoding"))
+
+	// 小响应和不支持压缩的客户端不压缩
+	assert.Empty(t, send("gzip", "small").Header().Get("Content-Encoding"))
+	w = send("", large)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	// 参与协商但未压缩的响应同样设置 Vary，避免缓存混用两种副本
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+	// 已压缩的内容类型不再压缩
+	w = httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept-Encoding", "gzip")
+	response.Binary(c, "image/png", bytes.Repeat([]byte{1}, 512))
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "512", w.Header().Get("Content-Length"))
+}
+
+func TestErrorFormattedMessage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+	response.RegisterMessages("zh-CN", map[int]string{errspec.ErrRecordNotExist.Code(): "记录不存在"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept-Language", "zh-CN")
+
+	err := errspec.Newf(context.Background(), errspec.ErrRecordNotExist, "user %d not found", 42)
+	assert.Equal(t, errspec.ErrRecordNotExist.Code(), err.Code())
+	response.Error(c, err)
+
+	// 自定义消息原样返回，错误码不变，日志中同时记录原始消息
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, errspec.ErrRecordNotExist.Code(), result.Code)
+	assert.Equal(t, "user 42 not found", result.Message)
+	assert.Contains(t, buf.String(), `"message":"user 42 not found"`)
+	assert.Contains(t, buf.String(), `"original_message":"`+err.OriginalMessage()+`"`)
+}
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		query    string
+		page     int
+		pageSize int
+	}{
+		{"", 1, response.DefaultPageSize},
+		{"page=3&page_size=50", 3, 50},
+		{"page=abc&page_size=-5", 1, response.DefaultPageSize},
+		{"page=0&page_size=0", 1, response.DefaultPageSize},
+		{"page=2&page_size=100000", 2, model.DefaultMaxPageSize},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+
+		page, pageSize := response.ParsePagination(c)
+		assert.Equal(t, tt.page, page, tt.query)
+		assert.Equal(t, tt.pageSize, pageSize, tt.query)
+	}
+}
+
+// codeOnlyError 只携带错误码的错误
+type codeOnlyError struct{ code int }
+
+func (e codeOnlyError) Error() string { return "code only" }
+func (e codeOnlyError) Code() int     { return e.code }
+
+func TestErrorRegistryStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	// 错误没有HTTP状态码时按错误码注册表确定
+	response.Error(c, codeOnlyError{code: errspec.ErrForbidden.Code()})
+
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, errspec.ErrForbidden.Code(), result.Code)
+}
+
+func TestTooManyRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	response.TooManyRequests(c, 1500*time.Millisecond)
+
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, errspec.ErrTooManyRequests.Code(), result.Code)
+	// 秒数向上取整
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+}
+
+func TestDownload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newContext := func(rangeHeader string) (*gin.Context, *httptest.ResponseRecorder) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		if rangeHeader != "" {
+			c.Request.Header.Set("Range", rangeHeader)
+		}
+		return c, w
+	}
+
+	// 可定位的内容支持 Range 请求
+	c, w := newContext("bytes=6-")
+	response.Download(c, strings.NewReader("hello world"), "报表.txt", "text/plain")
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "world", w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "filename*=utf-8''")
+
+	// 不可定位的内容以流的形式输出
+	c, w = newContext("")
+	response.Download(c, io.MultiReader(strings.NewReader("hello "), strings.NewReader("world")), "a.csv", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello world", w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+
+	// 写入前出错时返回标准错误响应
+	c, w = newContext("")
+	response.Download(c, iotest.ErrReader(errspec.ErrFileStorage.New(context.Background())), "a.csv", "")
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, errspec.ErrFileStorage.Code(), result.Code)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestCursorPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	response.CursorPage(c, []int{1, 2}, "abc")
+
+	var result response.Result[response.CursorPageResult[[]int]]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, []int{1, 2}, result.Data.List)
+	assert.Equal(t, "abc", result.Data.NextCursor)
+	assert.True(t, result.Data.HasMore)
+	assert.Contains(t, w.Body.String(), `"next_cursor":"abc"`)
+
+	// 没有下一页
+	last := response.NewCursorPageResult([]int{}, "")
+	assert.False(t, last.HasMore)
+}
+
+func TestSuccessWithETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(method, ifNoneMatch, etag string, data map[string]int) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/", nil)
+		if ifNoneMatch != "" {
+			c.Request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		response.SuccessWithETag(c, data, etag)
+		return w
+	}
+
+	data := map[string]int{"version": 1}
+
+	// 首次请求返回完整响应和 ETag
+	w := serve(http.MethodGet, "", "", data)
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Equal(t, response.ComputeETag(data), etag)
+	var result response.Result[map[string]int]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 1, result.Data["version"])
+
+	// 数据未变化时返回 304 且没有响应体
+	w = serve(http.MethodGet, `"other", `+etag, "", data)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	// 数据变化后 ETag 不再匹配
+	w = serve(http.MethodGet, etag, "", map[string]int{"version": 2})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	// 指定的 ETag 自动加引号，If-None-Match 使用弱比较
+	w = serve(http.MethodGet, `W/"v1"`, "v1", data)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"v1"`, w.Header().Get("ETag"))
+
+	// 非 GET 请求不返回 304
+	w = serve(http.MethodPost, `"v1"`, "v1", data)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSanitizeErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+	respond := func(err error) response.Result[struct{}] {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		response.Error(c, err)
+
+		var result response.Result[struct{}]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	leaky := pkgerrors.New(`pq: relation "internal_users" does not exist`)
+
+	// 默认原样返回
+	assert.Equal(t, leaky.Error(), respond(leaky).Message)
+
+	response.Configure(response.WithSanitizeErrors(true))
+	defer response.Configure(response.WithSanitizeErrors(false))
+
+	buf.Reset()
+	result := respond(leaky)
+	assert.Equal(t, "internal error", result.Message)
+	assert.Equal(t, errspec.ErrInternal.Code(), result.Code)
+	// 原始消息只记录到日志
+	assert.Contains(t, buf.String(), "internal_users")
+
+	// errspec 定义的错误不受影响，包括包装后的错误
+	ctx := context.Background()
+	assert.Equal(t, "resource does not exist", respond(errspec.ErrNotFound.New(ctx)).Message)
+	assert.Equal(t, "resource does not exist", respond(fmt.Errorf("load user: %w", errspec.ErrNotFound.New(ctx))).Message)
+}
+
+func TestQueryOptionsFromRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?age=gte:18&age=lte:60&status=in:1,2&name=like:50%25_off&city=Shanghai&time=10:30&email=&password=x", nil)
+
+	opts := response.QueryOptionsFromRequest(c, map[string]string{
+		"age":    "users.age",
+		"status": "users.status",
+		"name":   "users.username",
+		"city":   "users.city",
+		"time":   "users.login_time",
+		"email":  "users.email",
+	})
+
+	// 未列入映射的参数和空值被忽略，未知前缀按等值比较
+	assert.Equal(t, "users.age >= ? AND users.age <= ? AND users.city = ? AND users.username LIKE ? ESCAPE ? AND users.status IN ? AND users.login_time = ?", opts.Condition)
+	// like 值中的通配符被转义
+	assert.Equal(t, []any{"18", "60", "Shanghai", `%50\%\_off%`, `\`, []string{"1", "2"}, "10:30"}, opts.Args)
+
+	// 没有过滤参数时条件为空
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?page=2", nil)
+	opts = response.QueryOptionsFromRequest(c, map[string]string{"age": "age"})
+	assert.Empty(t, opts.Condition)
+	assert.Empty(t, opts.Args)
+}
+
+func TestRaw(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+	response.Configure(response.WithFieldNaming(response.CamelCase))
+	defer response.Configure(response.WithFieldNaming(response.SnakeCase))
+
+	// 成功时原样输出，不包装也不转换字段名，不记录错误
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/webhook", nil)
+	c.Set("request_id", "req-1")
+	response.Raw(c, http.StatusOK, map[string]bool{"received_ok": true})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"received_ok":true}`, w.Body.String())
+	assert.Equal(t, "req-1", w.Header().Get("X-Request-ID"))
+	assert.Empty(t, buf.String())
+
+	// 错误状态码记录日志，包含处理函数附加的错误链
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/webhook", nil)
+	_ = c.Error(pkgerrors.New("signature mismatch"))
+	response.Raw(c, http.StatusBadRequest, gin.H{"error": "invalid signature"})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"invalid signature"}`, w.Body.String())
+	out := buf.String()
+	assert.Contains(t, out, "API error occurred")
+	assert.Contains(t, out, `"status":400`)
+	assert.Contains(t, out, "signature mismatch")
+}
+
+func TestTraceIDFromSpan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+
+	// 有效的 Span 优先于 gin 上下文中设置的追踪ID
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request = c.Request.WithContext(trace.ContextWithSpanContext(c.Request.Context(), spanCtx))
+	c.Set("trace_id", "manual-trace")
+	response.Error(c, errspec.ErrNotFound.New(c.Request.Context()))
+
+	var result response.Result[struct{}]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, traceID.String(), result.TraceID)
+
+	// 没有 Span 时使用 gin 上下文中的追踪ID
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Set("trace_id", "manual-trace")
+	response.Error(c, errspec.ErrNotFound.New(c.Request.Context()))
+
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "manual-trace", result.TraceID)
+}
+
+func TestCreatedAndNoContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/users", nil)
+	c.Set("request_id", "req-1")
+
+	response.Created(c, userDTO{ID: 1, Username: "tom"})
+
+	var result response.Result[userDTO]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, errspec.Success.Code(), result.Code)
+	assert.Equal(t, "req-1", result.RequestID)
+	assert.Equal(t, userDTO{ID: 1, Username: "tom"}, result.Data)
+
+	// 204 不携带响应体，请求ID通过响应头返回
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	c.Set("request_id", "req-2")
+
+	response.NoContent(c)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+	assert.Equal(t, "req-2", w.Header().Get("X-Request-ID"))
+}
+
+func TestRegisterStatusMapper(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Cleanup(response.ResetStatusMappers)
+
+	respond := func(err error) (int, response.Result[struct{}]) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		response.Error(c, err)
+
+		var result response.Result[struct{}]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return w.Code, result
+	}
+
+	ctx := context.Background()
+	response.RegisterStatusMapper(func(err error) (int, bool) {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return http.StatusGatewayTimeout, true
+		}
+		return 0, false
+	})
+	// 与第一个映射重叠，按注册顺序第一个匹配的映射胜出
+	response.RegisterStatusMapper(func(err error) (int, bool) {
+		if errors.Is(err, context.DeadlineExceeded) || errspec.ErrNotFound.Is(err) {
+			return http.StatusGone, true
+		}
+		return 0, false
+	})
+
+	status, result := respond(fmt.Errorf("query users: %w", context.DeadlineExceeded))
+	assert.Equal(t, http.StatusGatewayTimeout, status)
+	assert.Equal(t, errspec.ErrUnknown.Code(), result.Code)
+
+	// 映射优先于错误自身的 HttpStatus()，错误码不变
+	status, result = respond(errspec.ErrNotFound.New(ctx))
+	assert.Equal(t, http.StatusGone, status)
+	assert.Equal(t, errspec.ErrNotFound.Code(), result.Code)
+
+	// 没有匹配的映射时使用错误自身的状态码
+	status, _ = respond(errspec.ErrForbidden.New(ctx))
+	assert.Equal(t, http.StatusForbidden, status)
+
+	response.ResetStatusMappers()
+	status, _ = respond(errspec.ErrNotFound.New(ctx))
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil)
+
+	ch := make(chan any, 3)
+	ch <- "hello"
+	ch <- "line1\nline2"
+	ch <- map[string]int{"count": 1}
+	close(ch)
+
+	// 通道关闭后结束
+	response.Stream(c, ch, response.WithHeartbeat(0))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "no", w.Header().Get("X-Accel-Buffering"))
+	// 多行数据拆分为多个 data 字段，非字符串值编码为 JSON
+	assert.Equal(t, "data: hello\n\ndata: line1\ndata: line2\n\ndata: {\"count\":1}\n\n", w.Body.String())
+
+	// 客户端断开时结束，即使通道未关闭
+	ctx, cancel := context.WithCancel(context.Background())
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		response.Stream(c, make(chan any), response.WithHeartbeat(0))
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stream did not return after the request context was canceled")
+	}
+}
+
+func TestErrorWithData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+	type rowError struct {
+		Row    int    `json:"row"`
+		Reason string `json:"reason"`
+	}
+	rows := []rowError{{Row: 2, Reason: "duplicate email"}}
+	err := errspec.ErrValidation.New(context.Background())
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/import", nil)
+	response.ErrorWithData(c, err, rows)
+
+	var result response.Result[[]rowError]
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, errspec.ErrValidation.Code(), result.Code)
+	assert.Equal(t, rows, result.Data)
+
+	// 与 Error 记录相同的错误日志
+	withData := buf.String()
+	buf.Reset()
+	c, _ = gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/import", nil)
+	response.Error(c, err)
+
+	var got, want map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(withData), &got))
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &want))
+	for _, key := range []string{"msg", "level", "code", "message", "path", "method", "error_chain"} {
+		assert.Equal(t, want[key], got[key], key)
+	}
+}
+
+func TestSuccessDefaults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	response.Configure(response.WithSuccessCode(200), response.WithSuccessMessage("ok"))
+	defer response.Configure(response.WithSuccessCode(0), response.WithSuccessMessage("success"))
+
+	respond := func(write func(c *gin.Context)) response.Result[any] {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		write(c)
+
+		var result response.Result[any]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		return result
+	}
+
+	result := respond(func(c *gin.Context) { response.Success(c, 1) })
+	assert.Equal(t, 200, result.Code)
+	assert.Equal(t, "ok", result.Message)
+
+	result = respond(func(c *gin.Context) { response.SuccessNoData(c) })
+	assert.Equal(t, 200, result.Code)
+	assert.Equal(t, "ok", result.Message)
+
+	// 调用时传入的消息优先
+	result = respond(func(c *gin.Context) { response.SuccessNoData(c, "deleted") })
+	assert.Equal(t, 200, result.Code)
+	assert.Equal(t, "deleted", result.Message)
+}
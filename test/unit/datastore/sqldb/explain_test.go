@@ -0,0 +1,95 @@
+package sqldb_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/limitcool/starter/internal/datastore/sqldb"
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type product struct {
+	ID   uint
+	Name string
+}
+
+func TestExplainPlugin(t *testing.T) {
+	var buf bytes.Buffer
+	old := logger.Default()
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+	defer logger.SetDefault(old)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	// 内存数据库只在单个连接内可见
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&product{}))
+
+	// 默认阈值极大，products 表单独设置极小阈值，与 SlowQueryPlugin 使用相同的阈值
+	require.NoError(t, db.Use(sqldb.NewExplainPlugin(time.Hour, map[string]time.Duration{
+		"products": time.Nanosecond,
+	})))
+
+	// 写操作不应触发 EXPLAIN
+	require.NoError(t, db.Create(&product{Name: "book"}).Error)
+	assert.NotContains(t, buf.String(), "Slow query plan")
+
+	var products []product
+	require.NoError(t, db.Where("name = ?", "book").Find(&products).Error)
+
+	assert.Contains(t, buf.String(), "Slow query plan")
+	assert.Contains(t, buf.String(), "SCAN")
+
+	// 其他表使用默认阈值，不记录执行计划
+	buf.Reset()
+	var count int64
+	require.NoError(t, db.Table("sqlite_master").Count(&count).Error)
+	assert.NotContains(t, buf.String(), "Slow query plan")
+}
+
+func TestSlowQueryPlugin(t *testing.T) {
+	var buf bytes.Buffer
+	old := logger.Default()
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+	defer logger.SetDefault(old)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	require.NoError(t, err)
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&product{}))
+
+	// 默认阈值极大，products 表单独设置极小阈值
+	require.NoError(t, db.Use(sqldb.NewSlowQueryPlugin(time.Hour, map[string]time.Duration{
+		"products": time.Nanosecond,
+	})))
+
+	require.NoError(t, db.Create(&product{Name: "book"}).Error)
+	assert.Contains(t, buf.String(), "Slow query")
+	assert.Contains(t, buf.String(), `"table":"products"`)
+	assert.Contains(t, buf.String(), `"rows":1`)
+	assert.Contains(t, buf.String(), "INSERT INTO")
+
+	// 其他表使用默认阈值，不记录
+	buf.Reset()
+	var count int64
+	require.NoError(t, db.Table("sqlite_master").Count(&count).Error)
+	assert.NotContains(t, buf.String(), "Slow query")
+}
@@ -0,0 +1,47 @@
+package sqldb_test
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/limitcool/starter/internal/datastore/sqldb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestRegisterDBStats(t *testing.T) {
+	open := func() *gorm.DB {
+		db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+			Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+		})
+		require.NoError(t, err)
+		return db
+	}
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, sqldb.RegisterDBStats(reg, open(), "primary"))
+	require.NoError(t, sqldb.RegisterDBStats(reg, open(), "replica"))
+
+	// 同名数据库重复注册会失败
+	assert.Error(t, sqldb.RegisterDBStats(reg, open(), "primary"))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	names := map[string]int{}
+	for _, family := range families {
+		names[family.GetName()] = len(family.GetMetric())
+	}
+	for _, name := range []string{
+		"go_sql_open_connections",
+		"go_sql_in_use_connections",
+		"go_sql_idle_connections",
+		"go_sql_wait_count_total",
+		"go_sql_wait_duration_seconds_total",
+	} {
+		assert.Equal(t, 2, names[name], name)
+	}
+}
@@ -0,0 +1,44 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/middleware"
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.Use(middleware.ContextLogger())
+	r.GET("/orders", func(c *gin.Context) {
+		logger.FromGin(c).Info("listing orders")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set(middleware.HeaderRequestID, "req-42")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, "listing orders", entry["msg"])
+	assert.Equal(t, http.MethodGet, entry["method"])
+	assert.Equal(t, "/orders", entry["path"])
+	assert.Equal(t, "req-42", entry["request_id"])
+}
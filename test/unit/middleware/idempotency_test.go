@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/middleware"
+	"github.com/limitcool/starter/internal/pkg/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := middleware.NewCacheIdempotencyStore(cache.NewMemoryCache())
+	calls := 0
+	failNext := false
+
+	r := gin.New()
+	r.Use(middleware.Idempotency(store, time.Minute))
+	r.POST("/orders", func(c *gin.Context) {
+		calls++
+		if failNext {
+			failNext = false
+			c.JSON(http.StatusInternalServerError, gin.H{"calls": calls})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"calls": calls})
+	})
+	r.PUT("/orders", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+
+	send := func(method, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/orders", nil)
+		if key != "" {
+			req.Header.Set(middleware.HeaderIdempotencyKey, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// 首次请求执行处理器，重复请求重放保存的响应
+	first := send(http.MethodPost, "k1")
+	assert.Equal(t, http.StatusCreated, first.Code)
+	replay := send(http.MethodPost, "k1")
+	assert.Equal(t, http.StatusCreated, replay.Code)
+	assert.Equal(t, first.Body.String(), replay.Body.String())
+	assert.Equal(t, "true", replay.Header().Get(middleware.HeaderIdempotentReplayed))
+	assert.Equal(t, 1, calls)
+
+	// 不同方法的相同幂等键互不影响
+	send(http.MethodPut, "k1")
+	assert.Equal(t, 2, calls)
+
+	// 未携带幂等键时每次都执行
+	send(http.MethodPost, "")
+	assert.Equal(t, 3, calls)
+
+	// 失败的响应不保存，客户端可以重试
+	failNext = true
+	assert.Equal(t, http.StatusInternalServerError, send(http.MethodPost, "k2").Code)
+	retry := send(http.MethodPost, "k2")
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Contains(t, retry.Body.String(), strconv.Itoa(calls))
+	assert.Equal(t, 5, calls)
+}
@@ -0,0 +1,74 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/middleware"
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevelOverride(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.InfoLevel, logger.JSONFormat))
+
+	r := gin.New()
+	r.Use(middleware.ContextLogger())
+	r.Use(middleware.LogLevelOverride(
+		middleware.WithLogLevelAllowedIPs("10.0.0.0/8"),
+		middleware.WithLogLevelToken("secret"),
+	))
+	r.GET("/orders", func(c *gin.Context) {
+		logger.DebugContext(c.Request.Context(), "debug from context")
+		logger.FromGin(c).Debug("debug from gin")
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(remoteAddr string, headers map[string]string) string {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.RemoteAddr = remoteAddr
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		return buf.String()
+	}
+
+	// 没有请求头时使用全局级别
+	assert.NotContains(t, serve("10.1.2.3:1234", nil), "debug from")
+
+	// 允许的网段
+	out := serve("10.1.2.3:1234", map[string]string{middleware.HeaderLogLevel: "debug"})
+	assert.Contains(t, out, "debug from context")
+	assert.Contains(t, out, "debug from gin")
+
+	// 不在允许的网段且没有令牌时忽略请求头
+	assert.NotContains(t, serve("192.168.1.1:1234", map[string]string{middleware.HeaderLogLevel: "debug"}), "debug from")
+
+	// 令牌正确时允许
+	out = serve("192.168.1.1:1234", map[string]string{
+		middleware.HeaderLogLevel:      "debug",
+		middleware.HeaderLogLevelToken: "secret",
+	})
+	assert.Contains(t, out, "debug from context")
+
+	// 令牌错误
+	assert.NotContains(t, serve("192.168.1.1:1234", map[string]string{
+		middleware.HeaderLogLevel:      "debug",
+		middleware.HeaderLogLevelToken: "wrong",
+	}), "debug from")
+
+	// 其他请求不受影响
+	logger.Debug("global debug")
+	assert.NotContains(t, buf.String(), "global debug")
+}
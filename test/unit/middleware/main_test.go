@@ -0,0 +1,15 @@
+package middleware_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+)
+
+// TestMain 使用丢弃输出的默认日志记录器，未设置日志记录器的测试也能记录错误日志
+func TestMain(m *testing.M) {
+	logger.SetDefault(logger.NewZapLogger(io.Discard, logger.InfoLevel, logger.TextFormat))
+	os.Exit(m.Run())
+}
@@ -0,0 +1,39 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	defer gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.RequestID(), middleware.PanicRecovery())
+	r.GET("/panic", func(c *gin.Context) {
+		panic("secret connection string")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	var result struct {
+		Code      int    `json:"code"`
+		RequestID string `json:"request_id"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, errspec.ErrUnknown.Code(), result.Code)
+	assert.Equal(t, w.Header().Get(middleware.HeaderRequestID), result.RequestID)
+}
@@ -0,0 +1,50 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/limitcool/starter/internal/middleware"
+	"github.com/limitcool/starter/internal/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var ginID, ctxID string
+	r := gin.New()
+	r.Use(middleware.RequestID())
+	r.GET("/", func(c *gin.Context) {
+		ginID = c.GetString("request_id")
+		ctxID = ctxutil.RequestID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(requestID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if requestID != "" {
+			req.Header.Set(middleware.HeaderRequestID, requestID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Header().Get(middleware.HeaderRequestID)
+	}
+
+	// 复用上游传入的请求ID
+	assert.Equal(t, "req-42", serve("req-42"))
+	assert.Equal(t, "req-42", ginID)
+	assert.Equal(t, "req-42", ctxID)
+
+	// 没有传入时生成新的ID
+	generated := serve("")
+	_, err := uuid.Parse(generated)
+	require.NoError(t, err)
+	assert.Equal(t, generated, ginID)
+	assert.Equal(t, generated, ctxID)
+	assert.NotEqual(t, generated, serve(""))
+}
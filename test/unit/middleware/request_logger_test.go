@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/limitcool/starter/internal/middleware"
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLoggerMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+	r := gin.New()
+	r.Use(middleware.RequestLoggerMiddleware(
+		middleware.WithSkipPaths("/health"),
+		middleware.WithBodyLogging(8),
+	))
+	r.GET("/health", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+	upload := &countingReader{Reader: strings.NewReader(strings.Repeat("x", 1<<20))}
+	r.POST("/echo", func(c *gin.Context) {
+		body, _ := c.GetRawData()
+		c.String(http.StatusOK, string(body))
+	})
+	r.POST("/upload", func(c *gin.Context) {
+		// 进入处理器前只读取了需要记录的部分
+		read := upload.n
+		body, _ := c.GetRawData()
+		c.JSON(http.StatusOK, gin.H{"read_before_handler": read, "size": len(body)})
+	})
+
+	// 健康检查不记录日志
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, buf.String())
+
+	// 处理器仍能读取完整的请求体，日志中的请求体和响应体被截断
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader("hello world")))
+	assert.Equal(t, "hello world", w.Body.String())
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "/echo", entry["path"])
+	assert.Equal(t, "hello wo", entry["request_body"])
+	assert.Equal(t, "hello wo", entry["response_body"])
+	assert.NotEmpty(t, entry["trace_id"])
+
+	// 大请求体不会被整个读入内存，处理器仍能读到完整内容
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", upload))
+	assert.JSONEq(t, `{"read_before_handler":8,"size":1048576}`, w.Body.String())
+}
+
+// countingReader 记录已读取字节数的读取器
+type countingReader struct {
+	io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += n
+	return n, err
+}
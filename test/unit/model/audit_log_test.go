@@ -0,0 +1,61 @@
+package model_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAuditLogs(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &model.AuditLog{})
+	repo := model.NewAuditLogRepo(db)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	logs := []model.AuditLog{
+		{ActorID: "1", Action: "create", EntityType: "order", EntityID: "10", CreatedAt: base},
+		{ActorID: "1", Action: "update", EntityType: "order", EntityID: "10", CreatedAt: base.Add(time.Hour)},
+		{ActorID: "2", Action: "update", EntityType: "user", EntityID: "5", CreatedAt: base.Add(2 * time.Hour)},
+		{ActorID: "1", Action: "delete", EntityType: "order", EntityID: "11", CreatedAt: base.Add(3 * time.Hour)},
+	}
+	require.NoError(t, db.Create(&logs).Error)
+
+	// 按操作者过滤，按时间倒序分页
+	result, err := repo.ListAuditLogs(ctx, model.AuditLogFilter{ActorID: "1"}, "", 2)
+	require.NoError(t, err)
+	require.Len(t, result.List, 2)
+	assert.Equal(t, "delete", result.List[0].Action)
+	assert.Equal(t, "update", result.List[1].Action)
+	require.NotEmpty(t, result.NextCursor)
+
+	result, err = repo.ListAuditLogs(ctx, model.AuditLogFilter{ActorID: "1"}, result.NextCursor, 2)
+	require.NoError(t, err)
+	require.Len(t, result.List, 1)
+	assert.Equal(t, "create", result.List[0].Action)
+	assert.Empty(t, result.NextCursor)
+
+	// 组合实体和时间范围
+	result, err = repo.ListAuditLogs(ctx, model.AuditLogFilter{
+		EntityType: "order",
+		EntityID:   "10",
+		Since:      base.Add(30 * time.Minute),
+		Until:      base.Add(3 * time.Hour),
+	}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, result.List, 1)
+	assert.Equal(t, "update", result.List[0].Action)
+
+	// 过滤值以参数绑定
+	result, err = repo.ListAuditLogs(ctx, model.AuditLogFilter{Action: "update' OR '1'='1"}, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, result.List)
+
+	// 起始时间晚于结束时间
+	_, err = repo.ListAuditLogs(ctx, model.AuditLogFilter{Since: base.Add(time.Hour), Until: base}, "", 10)
+	assert.True(t, errspec.ErrInvalidTimeRange.Is(err))
+}
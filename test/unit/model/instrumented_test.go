@@ -0,0 +1,67 @@
+package model_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/limitcool/starter/internal/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentedRepo(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+
+	reg := prometheus.NewRegistry()
+	metrics, err := model.NewRepoMetrics(reg)
+	require.NoError(t, err)
+
+	repo := model.NewInstrumentedRepo[testItem](model.NewGenericRepo[testItem](db), metrics)
+
+	item := testItem{Code: "a"}
+	require.NoError(t, repo.Create(ctx, &item))
+
+	_, err = repo.Get(ctx, item.ID, nil)
+	require.NoError(t, err)
+
+	// 记录不存在不计入错误
+	_, err = repo.Get(ctx, item.ID+100, nil)
+	require.Error(t, err)
+
+	// 无效的列名计入错误
+	_, err = repo.CountByGroup(ctx, "missing", nil)
+	require.Error(t, err)
+
+	assert.Equal(t, 3, testutil.CollectAndCount(reg, "repository_operation_duration_seconds"))
+	assert.Equal(t, 1, testutil.CollectAndCount(reg, "repository_operation_errors_total"))
+
+	// 每个方法记录为独立的操作
+	_, err = repo.Sum(ctx, "id", nil)
+	require.NoError(t, err)
+	_, err = repo.SumDecimal(ctx, "id", nil)
+	require.NoError(t, err)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	var operations []string
+	for _, family := range families {
+		if family.GetName() != "repository_operation_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "operation" {
+					operations = append(operations, label.GetValue())
+				}
+			}
+		}
+	}
+	assert.ElementsMatch(t, []string{"create", "get", "count_by_group", "sum", "sum_decimal"}, operations)
+
+	// 重复注册返回错误
+	_, err = model.NewRepoMetrics(reg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package model_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/model"
+	"github.com/limitcool/starter/internal/pkg/ctxutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+func TestReadFromPrimary(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	primaryDSN := filepath.Join(dir, "primary.db")
+	replicaDSN := filepath.Join(dir, "replica.db")
+
+	// 副本是一个独立的数据库，模拟尚未同步的只读副本
+	replica, err := gorm.Open(sqlite.Open(replicaDSN), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, replica.AutoMigrate(&testItem{}))
+
+	db, err := gorm.Open(sqlite.Open(primaryDSN), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&testItem{}))
+	require.NoError(t, db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{sqlite.Open(replicaDSN)},
+	})))
+
+	repo := model.NewGenericRepo[testItem](db)
+	item := &testItem{Code: "fresh", Name: "written"}
+	require.NoError(t, repo.Create(ctx, item))
+
+	// 默认读副本，读不到刚写入的数据
+	_, err = repo.Get(ctx, item.ID, nil)
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+
+	// 强制读主库
+	got, err := repo.Get(ctxutil.ReadFromPrimary(ctx), item.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh", got.Code)
+
+	count, err := repo.Count(ctxutil.ReadFromPrimary(ctx), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
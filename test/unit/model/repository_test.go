@@ -0,0 +1,1487 @@
+package model_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/limitcool/starter/internal/api/response"
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/model"
+	"github.com/limitcool/starter/internal/pkg/errorx"
+	applogger "github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/limitcool/starter/internal/pkg/options"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testItem 测试用实体
+type testItem struct {
+	ID   uint   `gorm:"primaryKey"`
+	Code string `gorm:"size:32"`
+	Name string `gorm:"size:64"`
+}
+
+func (testItem) TableName() string {
+	return "test_items"
+}
+
+// newTestDB 创建内存数据库
+func newTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// 内存数据库只在单个连接内可见
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(models...))
+	return db
+}
+
+func TestGetExactlyOne(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	items := []testItem{
+		{Code: "a", Name: "unique"},
+		{Code: "b", Name: "dup"},
+		{Code: "c", Name: "dup"},
+	}
+	require.NoError(t, db.Create(&items).Error)
+
+	testCases := []struct {
+		name    string
+		opts    *model.QueryOptions
+		wantErr func(err error) bool
+		want    string
+	}{
+		{
+			name: "Exactly one row",
+			opts: &model.QueryOptions{Condition: "name = ?", Args: []any{"unique"}},
+			want: "a",
+		},
+		{
+			name:    "Multiple rows",
+			opts:    &model.QueryOptions{Condition: "name = ?", Args: []any{"dup"}},
+			wantErr: errspec.ErrMultipleRows.Is,
+		},
+		{
+			name:    "No rows",
+			opts:    &model.QueryOptions{Condition: "name = ?", Args: []any{"missing"}},
+			wantErr: errspec.ErrRecordNotExist.Is,
+		},
+		{
+			name:    "Empty options",
+			opts:    nil,
+			wantErr: errspec.ErrQueryParamEmpty.Is,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			item, err := repo.GetExactlyOne(ctx, tc.opts)
+			if tc.wantErr != nil {
+				assert.Error(t, err)
+				assert.True(t, tc.wantErr(err))
+				assert.Nil(t, item)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, item.Code)
+		})
+	}
+}
+
+// legacyItem 使用非标准软删除列的测试实体
+type legacyItem struct {
+	ID        uint           `gorm:"primaryKey"`
+	Name      string         `gorm:"size:64"`
+	RemovedAt gorm.DeletedAt `gorm:"column:removed_at;index"`
+}
+
+func (legacyItem) TableName() string {
+	return "legacy_items"
+}
+
+func TestSoftDeleteCustomColumn(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &legacyItem{})
+	repo := model.NewGenericRepo[legacyItem](db)
+
+	item := &legacyItem{Name: "legacy"}
+	require.NoError(t, repo.Create(ctx, item))
+
+	// 软删除后正常查询不可见
+	require.NoError(t, repo.Delete(ctx, item.ID))
+	_, err := repo.Get(ctx, item.ID, nil)
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+
+	var removedAt *time.Time
+	require.NoError(t, db.Raw("SELECT removed_at FROM legacy_items WHERE id = ?", item.ID).Scan(&removedAt).Error)
+	assert.NotNil(t, removedAt)
+
+	trashed, err := repo.ListTrashed(ctx, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Len(t, trashed, 1)
+
+	// 恢复后重新可见
+	require.NoError(t, repo.Restore(ctx, item.ID))
+	restored, err := repo.Get(ctx, item.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", restored.Name)
+
+	trashed, err = repo.ListTrashed(ctx, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+
+	// 未删除的记录无法恢复
+	assert.True(t, errspec.ErrRecordNotExist.Is(repo.Restore(ctx, item.ID)))
+}
+
+func TestSoftDeleteNotSupported(t *testing.T) {
+	ctx := context.Background()
+	repo := model.NewGenericRepo[testItem](newTestDB(t, &testItem{}))
+
+	err := repo.Restore(ctx, 1)
+	assert.True(t, errspec.ErrSoftDeleteNotSupported.Is(err))
+}
+
+// itemSummary 临时表的行类型
+type itemSummary struct {
+	Name  string
+	Total int64
+}
+
+func (itemSummary) TableName() string {
+	return "item_summaries"
+}
+
+func TestCreateTempTable(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+
+	items := []testItem{
+		{Code: "a", Name: "x"},
+		{Code: "b", Name: "x"},
+		{Code: "c", Name: "y"},
+	}
+	require.NoError(t, db.Create(&items).Error)
+
+	// 事务外调用应返回错误
+	_, err := model.CreateTempTable[itemSummary](ctx, db, "tmp_summary", db.Model(&testItem{}))
+	assert.True(t, errspec.ErrNotInTransaction.Is(err))
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&testItem{}).Select("name, COUNT(*) AS total").Group("name")
+		repo, err := model.CreateTempTable[itemSummary](ctx, tx, "tmp_summary", query)
+		require.NoError(t, err)
+
+		summary, err := repo.Get(ctx, nil, &model.QueryOptions{Condition: "name = ?", Args: []any{"x"}})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), summary.Total)
+
+		count, err := repo.Count(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		return model.DropTempTable(ctx, tx, "tmp_summary")
+	})
+	require.NoError(t, err)
+}
+
+func TestPreloadLimits(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+	repo.MaxPreloads = 2
+
+	testCases := []struct {
+		name     string
+		preloads []string
+	}{
+		{name: "Too many preloads", preloads: []string{"A", "B", "C"}},
+		{name: "Too deep", preloads: []string{"A.B.C.D"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := repo.List(ctx, 1, 10, &model.QueryOptions{Preloads: tc.preloads})
+			assert.True(t, errspec.ErrTooManyPreloads.Is(err))
+		})
+	}
+}
+
+// skuItem 带唯一键的测试实体
+type skuItem struct {
+	ID   uint   `gorm:"primaryKey"`
+	SKU  string `gorm:"size:32;uniqueIndex"`
+	Name string `gorm:"size:64"`
+}
+
+func (skuItem) TableName() string {
+	return "sku_items"
+}
+
+func TestInsertMissing(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &skuItem{})
+	repo := model.NewGenericRepo[skuItem](db)
+
+	require.NoError(t, db.Create(&[]skuItem{{SKU: "A", Name: "old a"}, {SKU: "B", Name: "old b"}}).Error)
+
+	batch := []skuItem{
+		{SKU: "A", Name: "new a"},
+		{SKU: "B", Name: "new b"},
+		{SKU: "C", Name: "new c"},
+		{SKU: "D", Name: "new d"},
+	}
+	inserted, err := repo.InsertMissing(ctx, batch, []string{"sku"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), inserted)
+
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	// 已存在的记录保持不变
+	a, err := repo.Get(ctx, nil, &model.QueryOptions{Condition: "sku = ?", Args: []any{"A"}})
+	require.NoError(t, err)
+	assert.Equal(t, "old a", a.Name)
+
+	_, err = repo.InsertMissing(ctx, batch, []string{"sku; DROP TABLE sku_items"})
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+}
+
+func TestFindByStruct(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	items := []testItem{
+		{Code: "a", Name: ""},
+		{Code: "b", Name: "named"},
+		{Code: "c", Name: "named"},
+	}
+	require.NoError(t, db.Create(&items).Error)
+
+	// 非零值字段作为条件
+	list, total, err := repo.Find(ctx, &testItem{Name: "named"}, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, list, 1)
+
+	// 零值字段默认被忽略
+	_, total, err = repo.Find(ctx, &testItem{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	// 通过字段列表强制零值字段参与条件
+	list, total, err = repo.Find(ctx, &testItem{}, 1, 10, "Name")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, list, 1)
+	assert.Equal(t, "a", list[0].Code)
+
+	item, err := repo.FindOne(ctx, &testItem{Code: "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "named", item.Name)
+
+	_, err = repo.FindOne(ctx, &testItem{Code: "missing"})
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+}
+
+func TestGetForUpdate(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	item := testItem{Code: "a", Name: "stock"}
+	require.NoError(t, db.Create(&item).Error)
+
+	// 事务外调用返回错误
+	_, err := repo.GetForUpdate(ctx, item.ID, nil)
+	assert.True(t, errspec.ErrNotInTransaction.Is(err))
+
+	err = repo.Transaction(ctx, func(tx *gorm.DB) error {
+		locked, err := repo.WithTx(tx).GetForUpdate(ctx, item.ID, nil)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "a", locked.Code)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestGroupByHaving(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	items := []testItem{
+		{Code: "a", Name: "x"},
+		{Code: "b", Name: "x"},
+		{Code: "c", Name: "y"},
+		{Code: "d", Name: "z"},
+	}
+	require.NoError(t, db.Create(&items).Error)
+
+	count, err := repo.Count(ctx, &model.QueryOptions{GroupBy: []string{"name"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = repo.Count(ctx, &model.QueryOptions{
+		GroupBy:    []string{"name"},
+		Having:     "COUNT(*) > ?",
+		HavingArgs: []any{1},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+// itemTag 测试用子表实体
+type itemTag struct {
+	ID     uint `gorm:"primaryKey"`
+	ItemID uint
+	Label  string `gorm:"size:32"`
+}
+
+func (itemTag) TableName() string {
+	return "item_tags"
+}
+
+func TestDistinct(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{}, &itemTag{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	items := []testItem{{Code: "a"}, {Code: "b"}}
+	require.NoError(t, db.Create(&items).Error)
+	tags := []itemTag{
+		{ItemID: items[0].ID, Label: "hot"},
+		{ItemID: items[0].ID, Label: "new"},
+		{ItemID: items[1].ID, Label: "hot"},
+	}
+	require.NoError(t, db.Create(&tags).Error)
+
+	opts := &model.QueryOptions{
+		Joins:     []model.JoinClause{{Query: "JOIN item_tags ON item_tags.item_id = test_items.id"}},
+		Condition: "item_tags.label IN ?",
+		Args:      []any{[]string{"hot", "new"}},
+	}
+
+	count, err := repo.Count(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	opts.Distinct = true
+	list, err := repo.List(ctx, 1, 10, opts)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	count, err = repo.Count(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// SQLite 不支持 DISTINCT ON
+	_, err = repo.List(ctx, 1, 10, &model.QueryOptions{DistinctOn: []string{"code"}})
+	assert.True(t, errspec.ErrDistinctOnNotSupported.Is(err))
+}
+
+func TestPing(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	require.NoError(t, repo.Ping(ctx))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	err = repo.Ping(ctx)
+	assert.True(t, errspec.ErrDatabaseUnavailable.Is(err))
+}
+
+func TestCountByGroup(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	items := []testItem{
+		{Code: "a", Name: "x"},
+		{Code: "b", Name: "x"},
+		{Code: "c", Name: "y"},
+	}
+	require.NoError(t, db.Create(&items).Error)
+
+	counts, err := repo.CountByGroup(ctx, "name", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"x": 2, "y": 1}, counts)
+
+	counts, err = repo.CountByGroup(ctx, "name", &model.QueryOptions{Condition: "code <> ?", Args: []any{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"x": 1, "y": 1}, counts)
+
+	_, err = repo.CountByGroup(ctx, "name; DROP TABLE test_items", nil)
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+}
+
+func TestUpdateFields(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	item := testItem{Code: "a", Name: "keep"}
+	require.NoError(t, db.Create(&item).Error)
+
+	require.NoError(t, repo.UpdateFields(ctx, item.ID, map[string]any{"code": "b"}))
+
+	got, err := repo.Get(ctx, item.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "b", got.Code)
+	assert.Equal(t, "keep", got.Name)
+
+	// 零值同样会写入
+	require.NoError(t, repo.UpdateFields(ctx, item.ID, map[string]any{"Name": ""}))
+	got, err = repo.Get(ctx, item.ID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got.Name)
+
+	err = repo.UpdateFields(ctx, item.ID, map[string]any{"missing": 1})
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+}
+
+// taggedItem 带子表关联的测试实体
+type taggedItem struct {
+	ID   uint      `gorm:"primaryKey"`
+	Code string    `gorm:"size:32"`
+	Tags []itemTag `gorm:"foreignKey:ItemID"`
+}
+
+func (taggedItem) TableName() string {
+	return "test_items"
+}
+
+func TestPreloadSpec(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{}, &itemTag{})
+	repo := model.NewGenericRepo[taggedItem](db)
+
+	item := testItem{Code: "a"}
+	require.NoError(t, db.Create(&item).Error)
+	tags := []itemTag{
+		{ItemID: item.ID, Label: "b"},
+		{ItemID: item.ID, Label: "c"},
+		{ItemID: item.ID, Label: "a"},
+		{ItemID: item.ID, Label: "hidden"},
+	}
+	require.NoError(t, db.Create(&tags).Error)
+
+	got, err := repo.Get(ctx, item.ID, &model.QueryOptions{
+		PreloadSpecs: []model.PreloadSpec{{
+			Name:      "Tags",
+			Condition: "label <> ?",
+			Args:      []any{"hidden"},
+			OrderBy:   "label DESC",
+		}},
+	})
+	require.NoError(t, err)
+
+	labels := make([]string, 0, len(got.Tags))
+	for _, tag := range got.Tags {
+		labels = append(labels, tag.Label)
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, labels)
+}
+
+func TestBatchCreate(t *testing.T) {
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	items := []testItem{{Code: "a"}, {Code: "b"}, {Code: "c"}, {Code: "d"}, {Code: "e"}}
+	require.NoError(t, repo.BatchCreate(context.Background(), items, 2))
+
+	count, err := repo.Count(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+
+	// 已取消的上下文不再写入
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = repo.BatchCreate(ctx, []testItem{{Code: "f"}}, 2)
+	assert.True(t, errspec.ErrRequestCanceled.Is(err))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	count, err = repo.Count(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+}
+
+// paymentItem 带金额列的测试实体
+type paymentItem struct {
+	ID     uint            `gorm:"primaryKey"`
+	Status string          `gorm:"size:16"`
+	Amount decimal.Decimal `gorm:"type:decimal(10,2)"`
+}
+
+func (paymentItem) TableName() string {
+	return "payment_items"
+}
+
+func TestSum(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &paymentItem{})
+	repo := model.NewGenericRepo[paymentItem](db)
+
+	// 没有记录时结果为 0
+	total, err := repo.SumDecimal(ctx, "amount", nil)
+	require.NoError(t, err)
+	assert.True(t, total.IsZero())
+
+	payments := []paymentItem{
+		{Status: "paid", Amount: decimal.RequireFromString("10.25")},
+		{Status: "paid", Amount: decimal.RequireFromString("20.50")},
+		{Status: "refunded", Amount: decimal.RequireFromString("5.00")},
+	}
+	require.NoError(t, db.Create(&payments).Error)
+
+	opts := &model.QueryOptions{Condition: "status = ?", Args: []any{"paid"}}
+
+	total, err = repo.SumDecimal(ctx, "amount", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "30.75", total.StringFixed(2))
+
+	sum, err := repo.Sum(ctx, "amount", opts)
+	require.NoError(t, err)
+	assert.InDelta(t, 30.75, sum, 0.001)
+
+	_, err = repo.SumDecimal(ctx, "missing", nil)
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+}
+
+func TestNotFoundErrorCode(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+
+	// 指定错误定义时返回实体自己的错误码和消息，ErrRecordNotExist.Is 通过原因匹配
+	repo := model.NewGenericRepoWithNotFound[testItem](db, errspec.ErrUserNotFound)
+	_, err := repo.Get(ctx, 1, nil)
+	require.Error(t, err)
+	assert.True(t, errspec.ErrUserNotFound.Is(err))
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	var appErr *errorx.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errspec.ErrUserNotFound.Code(), appErr.Code())
+	assert.Equal(t, http.StatusNotFound, appErr.HttpStatus())
+	assert.Equal(t, errspec.ErrUserNotFound.New(ctx).Error(), appErr.Error())
+
+	// 默认返回 ErrRecordNotExist 的错误码
+	_, err = model.NewGenericRepo[testItem](db).Get(ctx, 1, nil)
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, errspec.ErrRecordNotExist.Code(), appErr.Code())
+	assert.False(t, errspec.ErrNotFound.Is(err))
+	assert.False(t, errspec.ErrUserNotFound.Is(err))
+}
+
+func TestNotFoundErrorWrappedResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := applogger.Default()
+	defer applogger.SetDefault(original)
+	applogger.SetDefault(applogger.NewZapLogger(io.Discard, applogger.InfoLevel, applogger.TextFormat))
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepoWithNotFound[testItem](db, errspec.ErrUserNotFound)
+
+	_, repoErr := repo.Get(ctx, 1, nil)
+	require.Error(t, repoErr)
+
+	// 服务层包装后，响应仍使用实体自己的错误码和消息
+	for _, err := range []error{repoErr, fmt.Errorf("load user: %w", repoErr)} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/users/1", nil)
+		response.Error(c, err)
+
+		var result response.Result[struct{}]
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, errspec.ErrUserNotFound.Code(), result.Code)
+		assert.Equal(t, errspec.ErrUserNotFound.New(ctx).Error(), result.Message)
+	}
+}
+
+func TestRaw(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, repo.Create(ctx, &testItem{Name: name}))
+	}
+
+	items, err := repo.Raw(ctx, "SELECT * FROM test_items WHERE name <> ? ORDER BY name DESC", "b")
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "c", items[0].Name)
+	assert.Equal(t, "a", items[1].Name)
+
+	var total int64
+	require.NoError(t, repo.RawScan(ctx, &total, "SELECT COUNT(*) FROM test_items"))
+	assert.Equal(t, int64(3), total)
+
+	assert.True(t, errspec.ErrQueryParamEmpty.Is(repo.RawScan(ctx, &total, " ")))
+}
+
+// auditedItem 记录删除人的测试实体
+type auditedItem struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:64"`
+	DeletedAt gorm.DeletedAt
+	DeletedBy *uint
+}
+
+func (auditedItem) TableName() string {
+	return "audited_items"
+}
+
+func TestDeleteBy(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &auditedItem{}, &legacyItem{}, &testItem{})
+
+	repo := model.NewGenericRepo[auditedItem](db)
+	item := &auditedItem{Name: "audited"}
+	require.NoError(t, repo.Create(ctx, item))
+
+	// 同时记录删除时间和删除人
+	require.NoError(t, repo.DeleteBy(ctx, item.ID, uint(7)))
+	_, err := repo.Get(ctx, item.ID, nil)
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+
+	var deleted auditedItem
+	require.NoError(t, db.Unscoped().First(&deleted, item.ID).Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+	require.NotNil(t, deleted.DeletedBy)
+	assert.Equal(t, uint(7), *deleted.DeletedBy)
+
+	// 已删除的记录不能重复删除
+	assert.True(t, errspec.ErrRecordNotExist.Is(repo.DeleteBy(ctx, item.ID, uint(8))))
+
+	// 没有 deleted_by 列时只记录删除时间
+	legacyRepo := model.NewGenericRepo[legacyItem](db)
+	legacy := &legacyItem{Name: "legacy"}
+	require.NoError(t, legacyRepo.Create(ctx, legacy))
+	require.NoError(t, legacyRepo.DeleteBy(ctx, legacy.ID, uint(7)))
+	_, err = legacyRepo.Get(ctx, legacy.ID, nil)
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+
+	// 不支持软删除的实体
+	err = model.NewGenericRepo[testItem](db).DeleteBy(ctx, 1, uint(7))
+	assert.True(t, errspec.ErrSoftDeleteNotSupported.Is(err))
+}
+
+func TestDeleteByIDs(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &legacyItem{})
+	repo := model.NewGenericRepo[legacyItem](db)
+
+	var ids []any
+	for _, name := range []string{"a", "b", "c"} {
+		item := &legacyItem{Name: name}
+		require.NoError(t, repo.Create(ctx, item))
+		ids = append(ids, item.ID)
+	}
+
+	// 空列表不删除任何记录
+	n, err := repo.DeleteByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	n, err = repo.DeleteByIDs(ctx, []any{ids[0], ids[1], 999})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// 软删除的记录仍可在回收站中找到
+	trashed, err := repo.ListTrashed(ctx, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Len(t, trashed, 2)
+}
+
+// itemView 投影用的视图结构体
+type itemView struct {
+	Name  string
+	Label string
+}
+
+func TestListInto(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, repo.Create(ctx, &testItem{Code: "x", Name: name}))
+	}
+
+	var views []itemView
+	err := model.ListInto(ctx, repo, &views, 1, 2, &model.QueryOptions{
+		Opts: []options.Option{
+			options.WithSelect("name, code || '-' || name AS label"),
+			options.WithOrder("name", "desc"),
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, views, 2)
+	assert.Equal(t, itemView{Name: "c", Label: "x-c"}, views[0])
+	assert.Equal(t, itemView{Name: "b", Label: "x-b"}, views[1])
+
+	err = model.ListInto(ctx, repo, &views, 0, 10, nil)
+	assert.True(t, errspec.ErrInvalidParams.Is(err))
+}
+
+func TestGetByIDs(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{}, &itemTag{})
+	repo := model.NewGenericRepo[taggedItem](db)
+
+	items := []testItem{{Code: "a"}, {Code: "b"}, {Code: "c"}}
+	require.NoError(t, db.Create(&items).Error)
+	require.NoError(t, db.Create(&itemTag{ItemID: items[0].ID, Label: "x"}).Error)
+
+	// 空列表不查询数据库
+	got, err := repo.GetByIDs(ctx, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	// 不存在的ID被忽略，预加载正常生效
+	got, err = repo.GetByIDs(ctx, []any{items[0].ID, items[2].ID, 999}, &model.QueryOptions{Preloads: []string{"Tags"}})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[items[0].ID].Code)
+	assert.Len(t, got[items[0].ID].Tags, 1)
+	assert.Equal(t, "c", got[items[2].ID].Code)
+}
+
+// defaultedItem 列带有数据库默认值的测试实体
+type defaultedItem struct {
+	ID     uint   `gorm:"primaryKey"`
+	Name   string `gorm:"size:64"`
+	Status string `gorm:"size:16;default:pending;->;<-:create"`
+}
+
+func (defaultedItem) TableName() string {
+	return "defaulted_items"
+}
+
+func TestCreateAndReload(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &defaultedItem{})
+	repo := model.NewGenericRepo[defaultedItem](db)
+
+	item := &defaultedItem{Name: "a"}
+	require.NoError(t, repo.CreateAndReload(ctx, item, nil))
+	assert.NotZero(t, item.ID)
+	assert.Equal(t, "pending", item.Status)
+
+	// 数据库中的值被修改后，保存并重新查询得到最新的行
+	require.NoError(t, db.Exec("UPDATE defaulted_items SET status = 'done' WHERE id = ?", item.ID).Error)
+	item.Name = "b"
+	require.NoError(t, repo.UpdateAndReload(ctx, item, nil))
+	assert.Equal(t, "b", item.Name)
+	assert.Equal(t, "done", item.Status)
+}
+
+func TestListWithCount(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+	repo.MaxPageSize = 2
+
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, repo.Create(ctx, &testItem{Name: name}))
+	}
+
+	// 超出范围的分页参数被限制，返回实际生效的值
+	page, err := repo.ListWithCount(ctx, 0, 1000, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, page.Page)
+	assert.Equal(t, 2, page.PageSize)
+	assert.Equal(t, int64(3), page.Total)
+	assert.Len(t, page.List, 2)
+
+	list, err := repo.List(ctx, 2, 1000, nil)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	page, err = repo.ListWithCount(ctx, 1, 10, &model.QueryOptions{Condition: "name = ?", Args: []any{"z"}})
+	require.NoError(t, err)
+	assert.Zero(t, page.Total)
+	assert.Empty(t, page.List)
+}
+
+// activity 按时间排序的动态测试实体
+type activity struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	Title     string `gorm:"size:64"`
+}
+
+func (activity) TableName() string {
+	return "activities"
+}
+
+func TestListByCursor(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &activity{})
+	repo := model.NewGenericRepo[activity](db)
+
+	// 多条记录的时间相同，需要按主键区分先后
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, offset := range []int{0, 1, 1, 1, 2} {
+		item := &activity{CreatedAt: base.Add(time.Duration(offset) * time.Minute), Title: strconv.Itoa(i)}
+		require.NoError(t, repo.Create(ctx, item))
+	}
+
+	orders := []model.CursorOrder{{Column: "created_at", Desc: true}}
+	var titles []string
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		result, err := repo.ListByCursor(ctx, cursor, 2, orders, nil)
+		require.NoError(t, err)
+		for _, item := range result.List {
+			titles = append(titles, item.Title)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	assert.Equal(t, []string{"4", "3", "2", "1", "0"}, titles)
+
+	_, err := repo.ListByCursor(ctx, "not-a-cursor", 2, orders, nil)
+	assert.True(t, errspec.ErrInvalidCursor.Is(err))
+
+	_, err = repo.ListByCursor(ctx, "", 2, []model.CursorOrder{{Column: "unknown"}}, nil)
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+}
+
+func TestOnlyDeleted(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &legacyItem{}, &testItem{})
+	repo := model.NewGenericRepo[legacyItem](db)
+
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, repo.Create(ctx, &legacyItem{Name: name}))
+	}
+	_, err := repo.DeleteByIDs(ctx, []any{uint(1), uint(2)})
+	require.NoError(t, err)
+
+	trash := &model.QueryOptions{OnlyDeleted: true}
+	list, err := repo.List(ctx, 1, 10, trash)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	count, err := repo.Count(ctx, trash)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Unscoped 包含所有记录
+	count, err = repo.Count(ctx, &model.QueryOptions{Unscoped: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	_, err = model.NewGenericRepo[testItem](db).Count(ctx, trash)
+	assert.True(t, errspec.ErrSoftDeleteNotSupported.Is(err))
+}
+
+func TestUpdateExisting(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	item := testItem{Code: "a", Name: "old"}
+	require.NoError(t, db.Create(&item).Error)
+
+	item.Name = ""
+	require.NoError(t, repo.UpdateExisting(ctx, &item))
+
+	// 零值同样会写入
+	got, err := repo.Get(ctx, item.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a", got.Code)
+	assert.Empty(t, got.Name)
+
+	// 主键不存在时不会插入新记录
+	err = repo.UpdateExisting(ctx, &testItem{ID: 999, Code: "x"})
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+
+	var count int64
+	require.NoError(t, db.Model(&testItem{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	err = repo.UpdateExisting(ctx, &testItem{Code: "x"})
+	assert.True(t, errspec.ErrQueryParamEmpty.Is(err))
+}
+
+// flagItem 带布尔列的测试实体
+type flagItem struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	IsActive  bool
+	ViewCount int64
+}
+
+func (flagItem) TableName() string {
+	return "flag_items"
+}
+
+func TestToggleBool(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &flagItem{})
+	repo := model.NewGenericRepo[flagItem](db)
+
+	item := flagItem{Name: "a"}
+	require.NoError(t, db.Create(&item).Error)
+
+	value, err := repo.ToggleBool(ctx, item.ID, "is_active")
+	require.NoError(t, err)
+	assert.True(t, value)
+
+	value, err = repo.ToggleBool(ctx, item.ID, "IsActive")
+	require.NoError(t, err)
+	assert.False(t, value)
+
+	_, err = repo.ToggleBool(ctx, item.ID, "name")
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+
+	_, err = repo.ToggleBool(ctx, 999, "is_active")
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+
+	// 列值为 NULL 时视为 false，翻转为 true
+	require.NoError(t, db.Exec("UPDATE flag_items SET is_active = NULL WHERE id = ?", item.ID).Error)
+	value, err = repo.ToggleBool(ctx, item.ID, "is_active")
+	require.NoError(t, err)
+	assert.True(t, value)
+
+	var stored sql.NullBool
+	require.NoError(t, db.Raw("SELECT is_active FROM flag_items WHERE id = ?", item.ID).Scan(&stored).Error)
+	assert.Equal(t, sql.NullBool{Bool: true, Valid: true}, stored)
+}
+
+func TestAffectedRows(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	item := testItem{Code: "a"}
+	require.NoError(t, db.Create(&item).Error)
+
+	item.Name = "b"
+	n, err := repo.UpdateE(ctx, &item)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	n, err = repo.DeleteE(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	// 删除不存在的记录不报错，影响行数为 0
+	n, err = repo.DeleteE(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestRunInTx(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+
+	id, err := model.RunInTx(ctx, db, func(tx *gorm.DB) (uint, error) {
+		item := testItem{Code: "a"}
+		err := tx.Create(&item).Error
+		return item.ID, err
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, id)
+
+	// 出错时回滚并返回零值
+	boom := errors.New("boom")
+	id, err = model.RunInTx(ctx, db, func(tx *gorm.DB) (uint, error) {
+		item := testItem{Code: "b"}
+		if err := tx.Create(&item).Error; err != nil {
+			return 0, err
+		}
+		return item.ID, boom
+	})
+	assert.ErrorIs(t, err, boom)
+	assert.Zero(t, id)
+
+	var count int64
+	require.NoError(t, db.Model(&testItem{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestIncrement(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &flagItem{})
+	repo := model.NewGenericRepo[flagItem](db)
+
+	item := flagItem{Name: "a", ViewCount: 10}
+	require.NoError(t, db.Create(&item).Error)
+
+	value, err := repo.Increment(ctx, item.ID, "view_count", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), value)
+
+	// 负数表示减少
+	value, err = repo.Increment(ctx, item.ID, "ViewCount", -3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), value)
+
+	_, err = repo.Increment(ctx, item.ID, "name", 1)
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+
+	_, err = repo.Increment(ctx, 999, "view_count", 1)
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+
+	// 模拟 MySQL 对值未变化的行返回影响行数 0，记录存在时仍返回当前值
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("test:unchanged_rows", func(tx *gorm.DB) {
+		tx.RowsAffected = 0
+	}))
+	value, err = repo.Increment(ctx, item.ID, "view_count", 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), value)
+
+	_, err = repo.Increment(ctx, 999, "view_count", 0)
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+}
+
+func TestIncrementPostgresReturning(t *testing.T) {
+	ctx := context.Background()
+
+	pgDB, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 user=test dbname=test"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	var statements []string
+	require.NoError(t, pgDB.Callback().Update().After("gorm:update").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}))
+
+	// Postgres 上在一条 UPDATE ... RETURNING 语句中返回新值，不开启事务重新读取
+	_, _ = model.NewGenericRepo[flagItem](pgDB).Increment(ctx, 1, "view_count", 5)
+	require.Len(t, statements, 1)
+	assert.Equal(t, `UPDATE "flag_items" SET "view_count"="view_count" + $1 WHERE "flag_items"."id" = $2 RETURNING "view_count"`, statements[0])
+}
+
+func TestListWithRelationCount(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{}, &itemTag{})
+	repo := model.NewGenericRepo[taggedItem](db)
+
+	items := []testItem{{Code: "a"}, {Code: "b"}}
+	require.NoError(t, db.Create(&items).Error)
+	tags := []itemTag{
+		{ItemID: items[0].ID, Label: "x"},
+		{ItemID: items[0].ID, Label: "y"},
+		{ItemID: items[0].ID, Label: "z"},
+	}
+	require.NoError(t, db.Create(&tags).Error)
+
+	list, counts, err := repo.ListWithRelationCount(ctx, "Tags", 1, 10, nil)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	// 关联记录不会被加载
+	assert.Empty(t, list[0].Tags)
+	assert.Equal(t, int64(3), counts[items[0].ID])
+	assert.Equal(t, int64(0), counts[items[1].ID])
+
+	_, _, err = repo.ListWithRelationCount(ctx, "Missing", 1, 10, nil)
+	assert.True(t, errspec.ErrInvalidRelation.Is(err))
+}
+
+func TestBatchUpsert(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &skuItem{})
+	repo := model.NewGenericRepo[skuItem](db)
+
+	require.NoError(t, db.Create(&[]skuItem{{SKU: "A", Name: "old a"}, {SKU: "B", Name: "old b"}}).Error)
+
+	batch := []skuItem{
+		{SKU: "A", Name: "new a"},
+		{SKU: "B", Name: "new b"},
+		{SKU: "C", Name: "new c"},
+	}
+	require.NoError(t, repo.BatchUpsert(ctx, batch, []string{"sku"}, []string{"name"}, 2))
+
+	count, err := repo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// 已存在的记录被更新
+	a, err := repo.Get(ctx, nil, &model.QueryOptions{Condition: "sku = ?", Args: []any{"A"}})
+	require.NoError(t, err)
+	assert.Equal(t, "new a", a.Name)
+
+	err = repo.BatchUpsert(ctx, batch, []string{"sku"}, []string{"missing"}, 2)
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+}
+
+func TestEach(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	items := []testItem{{Code: "a"}, {Code: "b"}, {Code: "c"}, {Code: "d"}, {Code: "e"}}
+	require.NoError(t, repo.BatchCreate(ctx, items, 0))
+
+	var codes []string
+	require.NoError(t, repo.Each(ctx, 2, &model.QueryOptions{Condition: "code <> ?", Args: []any{"c"}}, func(item testItem) error {
+		codes = append(codes, item.Code)
+		return nil
+	}))
+	assert.Equal(t, []string{"a", "b", "d", "e"}, codes)
+
+	var sizes []int
+	require.NoError(t, repo.EachBatch(ctx, 2, nil, func(batch []testItem) error {
+		sizes = append(sizes, len(batch))
+		return nil
+	}))
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+
+	// fn 返回错误时停止遍历
+	stop := errors.New("stop")
+	visited := 0
+	err := repo.Each(ctx, 2, nil, func(testItem) error {
+		visited++
+		if visited == 3 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 3, visited)
+}
+
+func TestDebugSQL(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	opts := &model.QueryOptions{Condition: "missing_column = ?", Args: []any{"needle"}}
+
+	// 未开启调试模式时返回原始错误
+	repo := model.NewGenericRepo[testItem](db)
+	_, err := repo.List(ctx, 1, 10, opts)
+	require.Error(t, err)
+	assert.False(t, errspec.ErrDatabase.Is(err))
+
+	require.NoError(t, repo.EnableDebugSQL())
+	require.NoError(t, repo.EnableDebugSQL())
+
+	_, err = repo.List(ctx, 1, 10, opts)
+	require.Error(t, err)
+	assert.True(t, errspec.ErrDatabase.Is(err))
+	assert.Equal(t, "database error", err.Error())
+	chain := errorx.FormatErrorChain(err)
+	assert.Contains(t, chain, "missing_column = \"needle\"")
+
+	// 记录不存在不属于执行失败
+	_, err = repo.Get(ctx, 1, nil)
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+	assert.False(t, errspec.ErrDatabase.Is(err))
+
+	// 未开启调试模式的仓库不受同一连接上注册的回调影响
+	_, err = model.NewGenericRepo[testItem](db).List(ctx, 1, 10, opts)
+	assert.False(t, errspec.ErrDatabase.Is(err))
+}
+
+func TestIndexHints(t *testing.T) {
+	ctx := context.Background()
+
+	// 非 MySQL 数据库忽略索引提示
+	sqliteRepo := model.NewGenericRepo[testItem](newTestDB(t, &testItem{}))
+	_, err := sqliteRepo.List(ctx, 1, 10, &model.QueryOptions{IndexHints: []string{"idx_code"}})
+	require.NoError(t, err)
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}))
+
+	repo := model.NewGenericRepo[testItem](db)
+	_, err = repo.List(ctx, 1, 10, &model.QueryOptions{
+		IndexHints: []string{"idx_code", "idx`name"},
+		ForceIndex: true,
+		Condition:  "code = ?",
+		Args:       []any{"a"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, sql, "FROM `test_items` FORCE INDEX (`idx_code`, `idx``name`) WHERE code = ?")
+}
+
+func TestDistinctValues(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	items := []testItem{{Code: "b", Name: "x"}, {Code: "a", Name: "x"}, {Code: "b", Name: "y"}, {Code: "c", Name: "y"}}
+	require.NoError(t, db.Create(&items).Error)
+
+	codes, err := model.DistinctValues[testItem, string](ctx, repo, "code", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, codes)
+
+	// 查询选项同样生效
+	codes, err = model.DistinctValues[testItem, string](ctx, repo, "Code", &model.QueryOptions{Condition: "name = ?", Args: []any{"y"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "c"}, codes)
+
+	_, err = model.DistinctValues[testItem, string](ctx, repo, "code; DROP TABLE test_items", nil)
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+}
+
+func TestDBRegistry(t *testing.T) {
+	ctx := context.Background()
+	mainDB := newTestDB(t, &testItem{})
+	analyticsDB := newTestDB(t, &testItem{})
+
+	registry := model.NewDBRegistry()
+	registry.Register(model.DefaultDBName, mainDB)
+	registry.Register("analytics", analyticsDB)
+	assert.Equal(t, []string{"analytics", model.DefaultDBName}, registry.Names())
+
+	require.NoError(t, analyticsDB.Create(&testItem{Code: "a"}).Error)
+
+	// 仓库只访问绑定的数据库
+	analyticsRepo, err := model.RepoFor[testItem](ctx, registry, "analytics")
+	require.NoError(t, err)
+	count, err := analyticsRepo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	mainRepo, err := model.RepoFor[testItem](ctx, registry, model.DefaultDBName)
+	require.NoError(t, err)
+	count, err = mainRepo.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	_, err = model.RepoFor[testItem](ctx, registry, "missing")
+	assert.True(t, errspec.ErrDatabaseNotRegistered.Is(err))
+
+	db, err := registry.Get(ctx, "analytics")
+	require.NoError(t, err)
+	assert.Same(t, analyticsDB, db)
+	_, err = registry.Get(ctx, "missing")
+	assert.True(t, errspec.ErrDatabaseNotRegistered.Is(err))
+
+	assert.NoError(t, registry.Close())
+}
+
+func TestWithTxKeepsContext(t *testing.T) {
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	var requestID any
+	require.NoError(t, db.Callback().Create().After("gorm:create").Register("test:request_id", func(tx *gorm.DB) {
+		requestID = tx.Statement.Context.Value("request_id")
+	}))
+
+	ctx := context.WithValue(context.Background(), "request_id", "req-1")
+	err := repo.Transaction(ctx, func(tx *gorm.DB) error {
+		// 事务内传入不含请求信息的上下文时，使用开启事务时的上下文补充
+		return repo.WithTx(tx).Create(context.Background(), &testItem{Code: "a"})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", requestID)
+}
+
+// profileItem 带 JSON 列的测试实体
+type profileItem struct {
+	ID      uint   `gorm:"primaryKey"`
+	Profile string `gorm:"type:json"`
+}
+
+func (profileItem) TableName() string {
+	return "profile_items"
+}
+
+func TestJSONConditions(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &profileItem{})
+	repo := model.NewGenericRepo[profileItem](db)
+
+	require.NoError(t, db.Create(&[]profileItem{
+		{Profile: `{"address":{"city":"Shanghai"},"age":30,"vip":true,"tags":["a","b"]}`},
+		{Profile: `{"address":{"city":"Beijing"},"age":25,"vip":false,"tags":["b","c"]}`},
+	}).Error)
+
+	ids := func(conds ...model.JSONCondition) []uint {
+		items, err := repo.List(ctx, 1, 10, &model.QueryOptions{JSONConditions: conds})
+		require.NoError(t, err)
+		result := make([]uint, 0, len(items))
+		for _, item := range items {
+			result = append(result, item.ID)
+		}
+		return result
+	}
+
+	assert.Equal(t, []uint{1}, ids(model.JSONEquals("profile", "address.city", "Shanghai")))
+	assert.Equal(t, []uint{2}, ids(model.JSONEquals("profile", "age", 25)))
+	assert.Equal(t, []uint{1}, ids(model.JSONEquals("profile", "vip", true)))
+	assert.Equal(t, []uint{2}, ids(model.JSONEquals("profile", "tags.1", "c")))
+	assert.Equal(t, []uint{1, 2}, ids(model.JSONContains("profile", "tags", "b")))
+	assert.Equal(t, []uint{2}, ids(model.JSONContains("profile", "tags", "c"), model.JSONEquals("profile", "vip", false)))
+
+	// 列和路径经过校验，不能用于注入
+	_, err := repo.List(ctx, 1, 10, &model.QueryOptions{JSONConditions: []model.JSONCondition{
+		model.JSONEquals("profile", "city') OR 1=1 --", "x"),
+	}})
+	assert.True(t, errspec.ErrInvalidJSONPath.Is(err))
+	_, err = repo.Count(ctx, &model.QueryOptions{JSONConditions: []model.JSONCondition{
+		model.JSONEquals("profile) OR (1=1", "city", "x"),
+	}})
+	assert.True(t, errspec.ErrInvalidColumn.Is(err))
+
+	// 未知的操作符和无法编码的值
+	_, err = repo.List(ctx, 1, 10, &model.QueryOptions{JSONConditions: []model.JSONCondition{
+		{Column: "profile", Path: "age", Op: "gt", Value: 1},
+	}})
+	assert.True(t, errspec.ErrInvalidJSONOperator.Is(err))
+	_, err = repo.List(ctx, 1, 10, &model.QueryOptions{JSONConditions: []model.JSONCondition{
+		model.JSONEquals("profile", "age", func() {}),
+	}})
+	assert.True(t, errspec.ErrInvalidJSONValue.Is(err))
+
+	mysqlDB, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	var sql string
+	require.NoError(t, mysqlDB.Callback().Query().After("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		sql = mysqlDB.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+	}))
+
+	_, err = model.NewGenericRepo[profileItem](mysqlDB).List(ctx, 1, 10, &model.QueryOptions{JSONConditions: []model.JSONCondition{
+		model.JSONEquals("profile", "address.city", "Shanghai"),
+		model.JSONContains("profile", "tags.0", "a"),
+	}})
+	require.NoError(t, err)
+	assert.Contains(t, sql, "JSON_EXTRACT(`profile_items`.`profile`, '$.address.city') = CAST('\"Shanghai\"' AS JSON)")
+	assert.Contains(t, sql, "JSON_CONTAINS(`profile_items`.`profile`, '\"a\"', '$.tags[0]')")
+}
+
+func TestEmptyResultsNotFound(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &legacyItem{})
+
+	// 模拟在结果为空时返回包装过的 ErrRecordNotFound 的 GORM 插件
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:raise_not_found", func(tx *gorm.DB) {
+		if tx.Error == nil && tx.RowsAffected == 0 {
+			_ = tx.AddError(fmt.Errorf("plugin: %w", gorm.ErrRecordNotFound))
+		}
+	}))
+
+	repo := model.NewGenericRepo[legacyItem](db)
+
+	list, err := repo.List(ctx, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	trashed, err := repo.ListTrashed(ctx, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+
+	page, err := repo.ListWithCount(ctx, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Zero(t, page.Total)
+
+	cursor, err := repo.ListByCursor(ctx, "", 10, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, cursor.List)
+
+	count, err := repo.Count(ctx, &model.QueryOptions{Distinct: true})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	sum, err := repo.Sum(ctx, "id", nil)
+	require.NoError(t, err)
+	assert.Zero(t, sum)
+
+	byIDs, err := repo.GetByIDs(ctx, []any{1, 2}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, byIDs)
+
+	found, total, err := repo.Find(ctx, &legacyItem{Name: "missing"}, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	assert.Zero(t, total)
+
+	names, err := model.DistinctValues[legacyItem, string](ctx, repo, "name", nil)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	var views []struct{ Name string }
+	require.NoError(t, model.ListInto(ctx, repo, &views, 1, 10, nil))
+	assert.Empty(t, views)
+
+	require.NoError(t, repo.Each(ctx, 10, nil, func(legacyItem) error { return nil }))
+
+	// 获取单个实体时转换为 ErrRecordNotExist
+	_, err = repo.Get(ctx, 1, nil)
+	assert.True(t, errspec.ErrRecordNotExist.Is(err))
+}
+
+func TestEstimateCount(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	require.NoError(t, repo.BatchCreate(ctx, []testItem{{Code: "a"}, {Code: "b"}, {Code: "c"}}, 0))
+
+	// 非 Postgres 数据库回退为精确统计
+	count, err := repo.EstimateCount(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = repo.EstimateCount(ctx, &model.QueryOptions{Condition: "code <> ?", Args: []any{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
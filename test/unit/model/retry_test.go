@@ -0,0 +1,116 @@
+package model_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/limitcool/starter/internal/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "MySQL deadlock", err: &mysql.MySQLError{Number: 1213}, want: true},
+		{name: "MySQL lock wait timeout", err: &mysql.MySQLError{Number: 1205}, want: true},
+		{name: "MySQL duplicate entry", err: &mysql.MySQLError{Number: 1062}, want: false},
+		{name: "Postgres serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "Postgres deadlock", err: &pgconn.PgError{Code: "40P01"}, want: true},
+		{name: "Postgres unique violation", err: &pgconn.PgError{Code: "23505"}, want: false},
+		{name: "Wrapped error", err: fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40P01"}), want: true},
+		{name: "Other error", err: errors.New("boom"), want: false},
+		{name: "Nil error", err: nil, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, model.IsRetryableError(tc.err))
+		})
+	}
+}
+
+func TestTransactionWithRetry(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	// 可重试的错误在重试后成功
+	attempts := 0
+	err := repo.TransactionWithRetry(ctx, 3, func(tx *gorm.DB) error {
+		attempts++
+		if attempts < 2 {
+			return &mysql.MySQLError{Number: 1213}
+		}
+		return tx.Create(&testItem{Code: "a"}).Error
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+
+	// 不可重试的错误立即返回
+	attempts = 0
+	boom := errors.New("boom")
+	err = repo.TransactionWithRetry(ctx, 3, func(tx *gorm.DB) error {
+		attempts++
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, 1, attempts)
+
+	// 超过重试次数后返回最后一次的错误
+	attempts = 0
+	err = repo.TransactionWithRetry(ctx, 2, func(tx *gorm.DB) error {
+		attempts++
+		return &pgconn.PgError{Code: "40001"}
+	})
+	assert.True(t, model.IsRetryableError(err))
+	assert.Equal(t, 3, attempts)
+}
+
+func TestTransactionWithOptions(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, &testItem{})
+	repo := model.NewGenericRepo[testItem](db)
+
+	// 默认隔离级别正常提交
+	err := repo.TransactionWithOptions(ctx, &sql.TxOptions{}, func(tx *gorm.DB) error {
+		return tx.Create(&testItem{Code: "a"}).Error
+	})
+	assert.NoError(t, err)
+
+	// 出错时回滚，非串行化隔离级别不重试
+	attempts := 0
+	err = repo.TransactionWithOptions(ctx, nil, func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(&testItem{Code: "b"}).Error; err != nil {
+			return err
+		}
+		return &mysql.MySQLError{Number: 1213}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+
+	count, err := repo.Count(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// 串行化隔离级别遇到序列化失败时重试
+	attempts = 0
+	err = repo.TransactionWithOptions(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, func(tx *gorm.DB) error {
+		attempts++
+		if attempts < 2 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
@@ -0,0 +1,37 @@
+package errorx_test
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/limitcool/starter/internal/errspec"
+	"github.com/limitcool/starter/internal/pkg/errorx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	spec, ok := errspec.Lookup(errspec.ErrRecordNotExist.Code())
+	require.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, spec.HttpStatus)
+	assert.NotEmpty(t, spec.Message)
+
+	_, ok = errspec.Lookup(-1)
+	assert.False(t, ok)
+
+	errorx.Register(errorx.Spec{Code: 99001, Message: "quota exceeded", HttpStatus: http.StatusPaymentRequired})
+	t.Cleanup(func() { errorx.Unregister(99001) })
+	spec, ok = errorx.LookupSpec(99001)
+	require.True(t, ok)
+	assert.Equal(t, "quota exceeded", spec.Message)
+
+	// 按错误码排序
+	all := errspec.All()
+	assert.True(t, sort.SliceIsSorted(all, func(i, j int) bool { return all[i].Code < all[j].Code }))
+	assert.Contains(t, all, spec)
+
+	errorx.Unregister(99001)
+	_, ok = errorx.LookupSpec(99001)
+	assert.False(t, ok)
+}
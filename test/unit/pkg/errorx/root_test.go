@@ -0,0 +1,53 @@
+package errorx_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/errorx"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootCode(t *testing.T) {
+	notFound := errorx.NewAppError(3001, "record not exist", http.StatusNotFound)
+	wrapped := fmt.Errorf("load order: %w", pkgerrors.WithMessage(notFound, "query"))
+
+	code, ok := errorx.RootCode(wrapped)
+	assert.True(t, ok)
+	assert.Equal(t, 3001, code)
+	assert.Same(t, notFound, errorx.RootError(wrapped))
+
+	// 多层业务错误时取最深层的错误码
+	outer := errorx.NewAppError(1001, "internal", http.StatusInternalServerError).Wrap(notFound)
+	code, ok = errorx.RootCode(outer)
+	assert.True(t, ok)
+	assert.Equal(t, 3001, code)
+
+	_, ok = errorx.RootCode(errors.New("plain"))
+	assert.False(t, ok)
+	assert.Nil(t, errorx.RootError(nil))
+}
+
+func TestNearestCode(t *testing.T) {
+	notFound := errorx.NewAppError(3001, "record not exist", http.StatusNotFound)
+	wrapped := fmt.Errorf("load order: %w", pkgerrors.WithMessage(notFound, "query"))
+
+	code, ok := errorx.NearestCode(wrapped)
+	assert.True(t, ok)
+	assert.Equal(t, 3001, code)
+	assert.Same(t, notFound, errorx.NearestError(wrapped))
+
+	// 多层业务错误时取最近的错误码，外层的转换不会被内层覆盖
+	outer := errorx.NewAppError(1001, "internal", http.StatusInternalServerError).Wrap(notFound)
+	code, ok = errorx.NearestCode(fmt.Errorf("handle: %w", outer))
+	assert.True(t, ok)
+	assert.Equal(t, 1001, code)
+	assert.Same(t, outer, errorx.NearestError(fmt.Errorf("handle: %w", outer)))
+
+	_, ok = errorx.NearestCode(errors.New("plain"))
+	assert.False(t, ok)
+	assert.Nil(t, errorx.NearestError(nil))
+}
@@ -0,0 +1,45 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/http/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDTransport(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(client.HeaderRequestID)
+	}))
+	defer server.Close()
+
+	ctx := context.WithValue(context.Background(), "request_id", "req-123")
+	httpClient := &http.Client{Transport: client.NewRequestIDTransport(nil)}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "req-123", got)
+	assert.Empty(t, req.Header.Get(client.HeaderRequestID), "原请求不应被修改")
+
+	// 已设置的请求头保持不变
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(client.HeaderRequestID, "custom")
+	resp, err = httpClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "custom", got)
+
+	// 辅助函数同样传递请求ID
+	_, err = client.GetJSON(ctx, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "req-123", got)
+}
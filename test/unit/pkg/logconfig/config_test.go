@@ -0,0 +1,43 @@
+package logconfig_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/limitcool/starter/pkg/logconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodStart(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 30, 0, 0, loc)
+	}
+	midnight := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, loc)
+	}
+
+	testCases := []struct {
+		name     string
+		rotation time.Duration
+		now      time.Time
+		want     time.Time
+	}{
+		{"按天", 24 * time.Hour, date(2024, 12, 31, 23), midnight(2024, 12, 31)},
+		{"按天跨年", 24 * time.Hour, date(2025, 1, 1, 0), midnight(2025, 1, 1)},
+		// 1970-01-01 起第 20088 天为 2024-12-31，三天周期为 12-31 ~ 01-02
+		{"三天周期起点", 72 * time.Hour, date(2024, 12, 31, 1), midnight(2024, 12, 31)},
+		{"三天周期跨年不重置", 72 * time.Hour, date(2025, 1, 1, 12), midnight(2024, 12, 31)},
+		{"三天周期末尾", 72 * time.Hour, date(2025, 1, 2, 23), midnight(2024, 12, 31)},
+		{"三天周期下一周期", 72 * time.Hour, date(2025, 1, 3, 0), midnight(2025, 1, 3)},
+		{"七天周期跨闰日", 7 * 24 * time.Hour, date(2024, 3, 1, 8), midnight(2024, 2, 29)},
+		{"按小时", time.Hour, date(2025, 1, 1, 10), time.Date(2025, 1, 1, 10, 0, 0, 0, loc)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := logconfig.FileLogConfig{Rotation: tc.rotation}
+			assert.True(t, tc.want.Equal(config.PeriodStart(tc.now)), "got %s", config.PeriodStart(tc.now))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package logger_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/limitcool/starter/pkg/logconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportCallerLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	config := logconfig.DefaultLogConfig()
+	config.Output = []string{"file"}
+	config.FileConfig.Path = path
+	config.ReportCaller = true
+	config.CallerLevel = logconfig.LogLevelWarn
+
+	l := logger.NewZapLoggerWithConfig(config)
+	l.Info("info message")
+	l.Warn("warn message")
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	callers := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		_, ok := entry["caller"]
+		callers[entry["msg"].(string)] = ok
+	}
+
+	assert.Equal(t, map[string]bool{"info message": false, "warn message": true}, callers)
+}
@@ -0,0 +1,53 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/limitcool/starter/pkg/logconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseFlushesFileOutput(t *testing.T) {
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	config := logconfig.DefaultLogConfig()
+	config.Output = []string{"file"}
+	config.FileConfig.Path = path
+
+	logger.SetDefault(logger.NewZapLoggerWithConfig(config))
+	logger.Error("last error before exit")
+
+	require.NoError(t, logger.Close())
+	// 重复关闭不报错
+	require.NoError(t, logger.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "last error before exit")
+}
+
+func TestFileOutputWithoutColor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	config := logconfig.DefaultLogConfig()
+	config.Output = []string{"file"}
+	config.FileConfig.Path = path
+	config.EncoderConfig.EncodeLevel = "color"
+
+	// 文件不是终端，color 编码方式不输出 ANSI 转义码
+	l := logger.NewZapLoggerWithConfig(config)
+	l.Warn("disk almost full")
+	require.NoError(t, l.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"level":"WARN"`)
+	assert.NotContains(t, string(data), "\x1b[")
+}
@@ -0,0 +1,82 @@
+package logger_test
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTerminal 打开一对伪终端，返回主端和从端，不支持时跳过测试
+func openTerminal(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo terminal unavailable: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	var unlock int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 {
+		t.Skipf("unlock pseudo terminal: %v", errno)
+	}
+	var n uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 {
+		t.Skipf("get pseudo terminal number: %v", errno)
+	}
+
+	slave, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("open pseudo terminal: %v", err)
+	}
+	t.Cleanup(func() { slave.Close() })
+	return master, slave
+}
+
+// logToTerminal 在标准输出为终端时写入一条 warn 日志，返回终端收到的内容
+func logToTerminal(t *testing.T) string {
+	t.Helper()
+	master, slave := openTerminal(t)
+	replaceStdout(t, slave)
+
+	l := logger.NewZapLoggerWithConfig(colorConsoleConfig())
+	l.Warn("disk almost full")
+
+	read := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 4096)
+		n, _ := master.Read(buf)
+		read <- string(buf[:n])
+	}()
+	select {
+	case output := <-read:
+		return output
+	case <-time.After(time.Second):
+		require.FailNow(t, "no output from terminal")
+		return ""
+	}
+}
+
+func TestConsoleOutputToTerminalWithColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	output := logToTerminal(t)
+	assert.Contains(t, output, "disk almost full")
+	assert.Contains(t, output, "\x1b[")
+}
+
+func TestConsoleOutputWithNoColor(t *testing.T) {
+	// 设置了 NO_COLOR 时即使输出到终端也不输出颜色
+	t.Setenv("NO_COLOR", "1")
+
+	output := logToTerminal(t)
+	assert.Contains(t, output, "WARN")
+	assert.Contains(t, output, "disk almost full")
+	assert.NotContains(t, output, "\x1b[")
+}
@@ -0,0 +1,59 @@
+package logger_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/limitcool/starter/pkg/logconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// colorConsoleConfig 返回输出到控制台并使用 color 级别编码的配置
+func colorConsoleConfig() logconfig.LogConfig {
+	config := logconfig.DefaultLogConfig()
+	config.Output = []string{"console"}
+	config.Format = logconfig.LogFormatText
+	config.EncoderConfig.EncodeLevel = "color"
+	return config
+}
+
+// replaceStdout 在测试期间把 os.Stdout 替换为 f，控制台输出在创建记录器时绑定 os.Stdout
+func replaceStdout(t *testing.T, f *os.File) {
+	t.Helper()
+	original := os.Stdout
+	os.Stdout = f
+	t.Cleanup(func() { os.Stdout = original })
+}
+
+func TestTextOutputToWriterWithoutColor(t *testing.T) {
+	// 写入器不是 *os.File 时不输出颜色
+	var buf bytes.Buffer
+	l := logger.NewZapLogger(&buf, logger.InfoLevel, logger.TextFormat)
+	l.Warn("disk almost full")
+
+	assert.Contains(t, buf.String(), "WARN")
+	assert.Contains(t, buf.String(), "disk almost full")
+	assert.NotContains(t, buf.String(), "\x1b[")
+}
+
+func TestConsoleOutputToPipeWithoutColor(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	replaceStdout(t, w)
+
+	// 标准输出被重定向到管道时不是终端，color 编码方式不输出 ANSI 转义码
+	l := logger.NewZapLoggerWithConfig(colorConsoleConfig())
+	l.Warn("disk almost full")
+	require.NoError(t, w.Close())
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "WARN")
+	assert.Contains(t, string(data), "disk almost full")
+	assert.NotContains(t, string(data), "\x1b[")
+}
@@ -0,0 +1,69 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFromContext(t *testing.T) {
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+	decode := func() map[string]any {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+		buf.Reset()
+		return entry
+	}
+
+	// 上下文中没有日志记录器时，从上下文中提取请求ID
+	ctx := context.WithValue(context.Background(), "request_id", "req-1")
+	logger.FromContext(ctx).Info("plain")
+	assert.Equal(t, "req-1", decode()["request_id"])
+
+	// 保存到上下文的日志记录器在各级别的包级函数中生效
+	ctx = logger.NewContext(ctx, logger.Default().WithField("order_id", 42))
+	for _, log := range []func(context.Context, string, ...any){
+		logger.DebugContext, logger.InfoContext, logger.WarnContext, logger.ErrorContext,
+	} {
+		log(ctx, "with logger")
+		entry := decode()
+		assert.Equal(t, "with logger", entry["msg"])
+		assert.EqualValues(t, 42, entry["order_id"])
+	}
+}
+
+func TestSpanTraceID(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled})
+
+	// 有效的 Span 优先于上下文中手动设置的追踪ID
+	ctx := context.WithValue(context.Background(), "trace_id", "manual-trace")
+	ctx = trace.ContextWithSpanContext(ctx, spanCtx)
+	log.WithContext(ctx).Info("with span")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, traceID.String(), entry["trace_id"])
+	assert.Equal(t, spanID.String(), entry["span_id"])
+
+	// 没有 Span 时使用上下文中的追踪ID
+	buf.Reset()
+	log.WithContext(context.WithValue(context.Background(), "trace_id", "manual-trace")).Info("without span")
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, "manual-trace", entry["trace_id"])
+}
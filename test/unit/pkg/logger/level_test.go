@@ -0,0 +1,71 @@
+package logger_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/limitcool/starter/pkg/logconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    logger.Level
+		wantErr bool
+	}{
+		{input: "debug", want: logger.DebugLevel},
+		{input: "INFO", want: logger.InfoLevel},
+		{input: "Warning", want: logger.WarnLevel},
+		{input: "warn", want: logger.WarnLevel},
+		{input: "err", want: logger.ErrorLevel},
+		{input: " fatal ", want: logger.FatalLevel},
+		{input: "verbose", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			level, err := logger.ParseLevel(tc.input)
+			if tc.wantErr {
+				assert.True(t, errors.Is(err, logger.ErrUnknownLevel))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, level)
+		})
+	}
+}
+
+func TestSetupE(t *testing.T) {
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	config := logconfig.DefaultLogConfig()
+	config.Level = "verbose"
+
+	err := logger.SetupE(config)
+	assert.True(t, errors.Is(err, logger.ErrUnknownLevel))
+
+	config.Level = "DEBUG"
+	assert.NoError(t, logger.SetupE(config))
+	assert.Equal(t, logger.DebugLevel, logger.Default().GetLevel())
+}
+
+func TestNew(t *testing.T) {
+	original := logger.Default()
+
+	config := logconfig.DefaultLogConfig()
+	config.Level = "verbose"
+	_, err := logger.New(config)
+	assert.True(t, errors.Is(err, logger.ErrUnknownLevel))
+
+	config.Level = "warn"
+	l, err := logger.New(config)
+	assert.NoError(t, err)
+	assert.Equal(t, logger.WarnLevel, l.GetLevel())
+
+	// 不替换默认日志记录器
+	assert.Same(t, original, logger.Default())
+}
@@ -0,0 +1,73 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogErrorLevel(t *testing.T) {
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	testCases := []struct {
+		level logger.Level
+		want  string
+	}{
+		{level: logger.InfoLevel, want: "INFO"},
+		{level: logger.WarnLevel, want: "WARN"},
+		{level: logger.ErrorLevel, want: "ERROR"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+
+			logger.LogErrorLevel(tc.level, "user not found", errors.New("record not found"), "user_id", 1)
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+			assert.Equal(t, tc.want, entry["level"])
+			assert.Equal(t, "record not found", entry["error"])
+			assert.EqualValues(t, 1, entry["user_id"])
+		})
+	}
+}
+
+func TestLogErrorStackFrames(t *testing.T) {
+	original := logger.Default()
+	defer logger.SetDefault(original)
+
+	originalStack := logger.GetStackTraceConfig()
+	defer logger.UpdateStackTraceConfig(originalStack.Enabled, originalStack.Level, originalStack.MaxStackFrames)
+	logger.UpdateStackTraceConfig(true, "error", 5)
+
+	err := pkgerrors.New("boom")
+
+	// JSON 格式输出帧对象数组
+	var buf bytes.Buffer
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat))
+	logger.LogErrorWithStack("failed", err)
+
+	var entry struct {
+		StackTrace []logger.StackFrame `json:"stack_trace"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.NotEmpty(t, entry.StackTrace)
+	assert.LessOrEqual(t, len(entry.StackTrace), 5)
+	assert.Contains(t, entry.StackTrace[0].Func, "TestLogErrorStackFrames")
+	assert.NotZero(t, entry.StackTrace[0].Line)
+
+	// 文本格式输出多行文本
+	buf.Reset()
+	logger.SetDefault(logger.NewZapLogger(&buf, logger.DebugLevel, logger.TextFormat))
+	logger.LogErrorWithStack("failed", err)
+	assert.Contains(t, buf.String(), `"stack_trace": "\n`)
+}
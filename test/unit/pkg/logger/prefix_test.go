@@ -0,0 +1,46 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/limitcool/starter/pkg/logconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceNameField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	config := logconfig.DefaultLogConfig()
+	config.Output = []string{"file"}
+	config.FileConfig.Path = path
+	config.ServiceName = "orders"
+
+	l := logger.NewZapLoggerWithConfig(config)
+	l.Info("order created")
+	l.WithField("order_id", 42).Info("order paid")
+	require.NoError(t, l.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(data, []byte(`"service":"orders"`)))
+	// 未配置 Prefix 时使用服务名作为前缀
+	assert.Contains(t, string(data), `"logger":"orders"`)
+}
+
+func TestCharmLoggerPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	logger.NewCharmLoggerWithConfig(&buf, logconfig.LogConfig{}).Info("hello")
+	assert.Contains(t, buf.String(), logger.DefaultCharmPrefix)
+
+	buf.Reset()
+	config := logconfig.LogConfig{Prefix: "billing-api", ServiceName: "billing"}
+	logger.NewCharmLoggerWithConfig(&buf, config).Info("hello")
+	assert.Contains(t, buf.String(), "billing-api")
+	assert.Contains(t, buf.String(), "service=billing")
+	assert.NotContains(t, buf.String(), logger.DefaultCharmPrefix)
+}
@@ -0,0 +1,93 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/limitcool/starter/pkg/logconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeRotateWriter(t *testing.T) {
+	dir := t.TempDir()
+	loc := time.Local
+
+	var mu sync.Mutex
+	now := time.Date(2024, 6, 10, 23, 30, 0, 0, loc)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	create := func(name string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0o644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+
+	// 历史周期的文件：未压缩的、过期的（按修改时间）、超出备份数量的，以及无关文件
+	create("app-2024-06-09.log.gz", time.Date(2024, 5, 1, 0, 0, 0, 0, loc))
+	create("app-2024-06-08.log", time.Date(2024, 6, 8, 23, 0, 0, 0, loc))
+	create("app-2024-06-07.log.gz", time.Date(2024, 6, 7, 23, 0, 0, 0, loc))
+	create("app-2024-06-06.log.gz", time.Date(2024, 6, 6, 23, 0, 0, 0, loc))
+	create("other.log", time.Date(2024, 1, 1, 0, 0, 0, 0, loc))
+
+	w := logger.NewTimeRotateWriter(logconfig.FileLogConfig{
+		Path:       filepath.Join(dir, "app.log"),
+		MaxSize:    10,
+		MaxAge:     7,
+		MaxBackups: 3,
+		Compress:   true,
+		Rotation:   24 * time.Hour,
+	}, clock)
+	defer w.Close()
+
+	_, err := w.Write([]byte("before midnight\n"))
+	require.NoError(t, err)
+	assert.True(t, exists("app-2024-06-10.log"))
+
+	// 清理在后台执行：压缩上一周期的文件，删除过期和超出备份数量的文件
+	require.Eventually(t, func() bool {
+		return exists("app-2024-06-08.log.gz") && !exists("app-2024-06-09.log.gz") && !exists("app-2024-06-06.log.gz")
+	}, time.Second, 10*time.Millisecond)
+	assert.False(t, exists("app-2024-06-08.log"))
+	assert.True(t, exists("app-2024-06-07.log.gz"))
+	assert.True(t, exists("other.log"))
+
+	// 跨越周期后写入新的带日期文件，当前周期的其他文件（如按大小轮转的备份）不受清理影响，
+	// 这里不使用 lumberjack 的备份文件名，避免被 lumberjack 按真实时间清理
+	currentBackup := "app-2024-06-11-manual.log"
+	create(currentBackup, time.Date(2024, 6, 11, 0, 5, 0, 0, loc))
+	create("app-2024-06-05.log.gz", time.Date(2024, 6, 5, 23, 0, 0, 0, loc))
+
+	mu.Lock()
+	now = time.Date(2024, 6, 11, 0, 10, 0, 0, loc)
+	mu.Unlock()
+
+	_, err = w.Write([]byte("after midnight\n"))
+	require.NoError(t, err)
+	assert.True(t, exists("app-2024-06-11.log"))
+
+	require.Eventually(t, func() bool {
+		return exists("app-2024-06-10.log.gz") && !exists("app-2024-06-05.log.gz")
+	}, time.Second, 10*time.Millisecond)
+	assert.False(t, exists("app-2024-06-10.log"))
+	assert.True(t, exists("app-2024-06-08.log.gz"))
+	assert.True(t, exists("app-2024-06-07.log.gz"))
+	assert.True(t, exists(currentBackup))
+	assert.True(t, exists("other.log"))
+
+	data, err := os.ReadFile(filepath.Join(dir, "app-2024-06-11.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "after midnight\n", string(data))
+}
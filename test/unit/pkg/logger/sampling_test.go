@@ -0,0 +1,35 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingLogger(t *testing.T) {
+	var buf bytes.Buffer
+	base := logger.NewZapLogger(&buf, logger.DebugLevel, logger.JSONFormat)
+
+	l := logger.NewSamplingLogger(base, logger.SamplingOptions{
+		Initial:    3,
+		Thereafter: 5,
+		Interval:   time.Minute,
+	})
+
+	// 前 3 条全部记录，之后每 5 条记录 1 条
+	for i := 0; i < 23; i++ {
+		l.Info("dependency failed")
+	}
+	// 不同消息独立计数，派生的记录器共享计数
+	l.WithField("k", "v").Info("another message")
+
+	assert.Equal(t, 3+4+1, strings.Count(buf.String(), "\n"))
+
+	stats := l.Stats()
+	assert.Equal(t, uint64(8), stats.Logged)
+	assert.Equal(t, uint64(16), stats.Suppressed)
+}
@@ -0,0 +1,63 @@
+//go:build !windows && !plan9
+
+package logger_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/limitcool/starter/internal/pkg/logger"
+	"github.com/limitcool/starter/pkg/logconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogOutput(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	config := logconfig.DefaultLogConfig()
+	config.Level = logconfig.LogLevelDebug
+	config.Output = []string{"syslog"}
+	config.Syslog = logconfig.SyslogConfig{
+		Network:  "udp",
+		Address:  conn.LocalAddr().String(),
+		Tag:      "starter",
+		Facility: "local0",
+	}
+
+	l := logger.NewZapLoggerWithConfig(config)
+	defer l.Close()
+
+	read := func() string {
+		buf := make([]byte, 4096)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	// 优先级 = 设施 local0(16) * 8 + 级别
+	l.Warn("disk almost full", "usage", 91)
+	msg := read()
+	assert.Contains(t, msg, "<132>")
+	assert.Contains(t, msg, "starter")
+	assert.Contains(t, msg, `"msg":"disk almost full"`)
+	assert.Contains(t, msg, `"usage":91`)
+
+	l.Error("request failed")
+	assert.Contains(t, read(), "<131>")
+}
+
+func TestSyslogUnknownFacility(t *testing.T) {
+	config := logconfig.DefaultLogConfig()
+	config.Output = []string{"syslog"}
+	config.Syslog.Facility = "unknown"
+
+	// syslog 不可用时回退到控制台输出，不影响创建日志记录器
+	l := logger.NewZapLoggerWithConfig(config)
+	require.NotNil(t, l)
+	assert.NoError(t, l.Close())
+}